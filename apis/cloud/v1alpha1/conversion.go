@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// v1alpha1 is the storage version of every kind, and the version the
+// controllers reconcile. Other versions are converted to and from it.
+
+// Hub marks this type as a conversion hub.
+func (*Firewall) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Network) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*PlacementGroup) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Server) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*Volume) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*ServerPool) Hub() {}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
 	"net"
 	"reflect"
 	"strconv"
@@ -28,6 +29,7 @@ import (
 	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/pkg/errors"
 
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -37,23 +39,32 @@ type FirewallParameters struct {
 	ApplyTo []FirewallApplyTo `json:"applyTo"`
 
 	// +kubebuilder:validation:Optional
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	Rules []FirewallRules `json:"rules"`
 }
 
+// +kubebuilder:validation:XValidation:rule="self.type != 'label_selector' || has(self.labels) || has(self.labelSelector)",message="labels or labelSelector is required for the label_selector type"
+// +kubebuilder:validation:XValidation:rule="!(has(self.labels) && has(self.labelSelector))",message="only one of labels or labelSelector may be set"
 type FirewallApplyTo struct {
 	Type hcloudsdk.FirewallResourceType `json:"type"`
 
 	// +kubebuilder:validation:Optional
 	ServerID *int64 `json:"serverID,omitempty"`
 
+	// Labels matches resources with all of the given labels. Use
+	// labelSelector for anything other than equality.
 	// +kubebuilder:validation:Optional
 	Labels *map[string]string `json:"labels,omitempty"`
+
+	// LabelSelector matches resources using Kubernetes label selector
+	// semantics, translated to the Hetzner label selector syntax.
+	// +kubebuilder:validation:Optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 }
 
-func (f *FirewallApplyTo) ToFirewallResource() hcloudsdk.FirewallResource {
+func (f *FirewallApplyTo) ToFirewallResource() (hcloudsdk.FirewallResource, error) {
 	var server *hcloudsdk.FirewallResourceServer
 	var labels *hcloudsdk.FirewallResourceLabelSelector
 
@@ -67,28 +78,89 @@ func (f *FirewallApplyTo) ToFirewallResource() hcloudsdk.FirewallResource {
 			Selector: hcloud.ToSelector(*f.Labels),
 		}
 	}
+	if f.LabelSelector != nil {
+		selector, err := hcloud.ToLabelSelector(f.LabelSelector)
+		if err != nil {
+			return hcloudsdk.FirewallResource{}, errors.Wrap(err, "error converting label selector")
+		}
+		labels = &hcloudsdk.FirewallResourceLabelSelector{
+			Selector: selector,
+		}
+	}
 
 	return hcloudsdk.FirewallResource{
 		Type:          f.Type,
 		Server:        server,
 		LabelSelector: labels,
-	}
+	}, nil
 }
 
+const (
+	// FirewallAnyIP matches every IPv4 and IPv6 address.
+	FirewallAnyIP = "any"
+	// FirewallAnyIPv4 matches every IPv4 address.
+	FirewallAnyIPv4 = "any-ipv4"
+	// FirewallAnyIPv6 matches every IPv6 address.
+	FirewallAnyIPv6 = "any-ipv6"
+)
+
+var firewallAnyIPs = map[string][]string{
+	FirewallAnyIP:   {"0.0.0.0/0", "::/0"},
+	FirewallAnyIPv4: {"0.0.0.0/0"},
+	FirewallAnyIPv6: {"::/0"},
+}
+
+// +kubebuilder:validation:XValidation:rule="self.direction != 'in' || !has(self.destinationIPs) || size(self.destinationIPs) == 0",message="destinationIPs cannot be set for in rules"
+// +kubebuilder:validation:XValidation:rule="self.direction != 'out' || !has(self.sourceIPs) || size(self.sourceIPs) == 0",message="sourceIPs cannot be set for out rules"
 type FirewallRules struct {
+	// +kubebuilder:validation:Enum:=in;out
 	Direction hcloudsdk.FirewallRuleDirection `json:"direction"`
-	Protocol  hcloudsdk.FirewallRuleProtocol  `json:"protocol"`
 
-	// +kubebuilder:validation:MinItems:=1
-	TargetIPs []string `json:"targetIPs"`
+	// +kubebuilder:validation:Enum:=tcp;udp;icmp;esp;gre
+	Protocol hcloudsdk.FirewallRuleProtocol `json:"protocol"`
+
+	// SourceIPs are the CIDRs traffic of an in rule is allowed from. Use
+	// any, any-ipv4 or any-ipv6 to match every address.
+	// +kubebuilder:validation:Optional
+	SourceIPs []string `json:"sourceIPs,omitempty"`
+
+	// DestinationIPs are the CIDRs traffic of an out rule is allowed to.
+	// Use any, any-ipv4 or any-ipv6 to match every address.
+	// +kubebuilder:validation:Optional
+	DestinationIPs []string `json:"destinationIPs,omitempty"`
+
+	// TargetIPs are the source IPs of an in rule or the destination IPs of
+	// an out rule.
+	// Deprecated: use sourceIPs or destinationIPs.
+	// +kubebuilder:validation:Optional
+	TargetIPs []string `json:"targetIPs,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	Description *string `json:"description,omitempty"`
 
+	// Port is the port or port range of a tcp or udp rule. Leaving it out
+	// matches every port, as all: true does. It cannot be set for any other
+	// protocol.
 	// +kubebuilder:validation:Optional
 	Port *FirewallPort `json:"port,omitempty"`
 }
 
+// RuleIPs returns the source and destination IPs of the rule, with the
+// deprecated target IPs added to whichever the direction uses.
+func (f *FirewallRules) RuleIPs() (source, destination []string) {
+	source = f.SourceIPs
+	destination = f.DestinationIPs
+
+	switch f.Direction {
+	case hcloudsdk.FirewallRuleDirectionIn:
+		source = append(append([]string{}, source...), f.TargetIPs...)
+	case hcloudsdk.FirewallRuleDirectionOut:
+		destination = append(append([]string{}, destination...), f.TargetIPs...)
+	}
+
+	return source, destination
+}
+
 func (f *FirewallRules) ToFirewallRule() (*hcloudsdk.FirewallRule, error) {
 	opts := hcloudsdk.FirewallRule{
 		Description: f.Description,
@@ -96,43 +168,128 @@ func (f *FirewallRules) ToFirewallRule() (*hcloudsdk.FirewallRule, error) {
 		Protocol:    f.Protocol,
 	}
 
+	if err := f.ValidatePort(); err != nil {
+		return nil, err
+	}
 	if f.Protocol == hcloudsdk.FirewallRuleProtocolTCP || f.Protocol == hcloudsdk.FirewallRuleProtocolUDP {
 		opts.Port = hcloudsdk.Ptr(f.Port.String())
 	}
 
-	targetIPs := []net.IPNet{}
-	for _, ip := range f.TargetIPs {
-		_, netip, err := net.ParseCIDR(ip)
-		if err != nil {
-			return nil, errors.Wrap(err, "error parsing firewall cidr")
-		}
-		targetIPs = append(targetIPs, *netip)
-	}
+	source, destination := f.RuleIPs()
 
+	var err error
 	switch f.Direction {
 	case hcloudsdk.FirewallRuleDirectionIn:
-		opts.SourceIPs = targetIPs
+		if len(destination) > 0 {
+			return nil, fmt.Errorf("in rules cannot have destination IPs")
+		}
+		if len(source) == 0 {
+			return nil, fmt.Errorf("in rules need at least one source IP")
+		}
+		opts.SourceIPs, err = ParseFirewallIPs(source)
 	case hcloudsdk.FirewallRuleDirectionOut:
-		opts.DestinationIPs = targetIPs
+		if len(source) > 0 {
+			return nil, fmt.Errorf("out rules cannot have source IPs")
+		}
+		if len(destination) == 0 {
+			return nil, fmt.Errorf("out rules need at least one destination IP")
+		}
+		opts.DestinationIPs, err = ParseFirewallIPs(destination)
+	default:
+		return nil, fmt.Errorf("unsupported firewall direction %q", f.Direction)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return &opts, nil
 }
 
+// ParseFirewallIPs parses CIDRs and the any, any-ipv4 and any-ipv6
+// shortcuts into the networks they match.
+func ParseFirewallIPs(ips []string) ([]net.IPNet, error) {
+	nets := []net.IPNet{}
+	for _, ip := range ips {
+		cidrs, ok := firewallAnyIPs[ip]
+		if !ok {
+			cidrs = []string{ip}
+		}
+		for _, cidr := range cidrs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrap(err, "error parsing firewall cidr")
+			}
+			nets = append(nets, *ipNet)
+		}
+	}
+	return nets, nil
+}
+
+// ValidatePort checks the rule's port is valid if its protocol uses ports,
+// and that there's no port if it does not. A tcp or udp rule without a port
+// matches every port.
+func (f *FirewallRules) ValidatePort() error {
+	switch f.Protocol {
+	case hcloudsdk.FirewallRuleProtocolTCP, hcloudsdk.FirewallRuleProtocolUDP:
+		if f.Port == nil {
+			return nil
+		}
+		return f.Port.Validate()
+	case hcloudsdk.FirewallRuleProtocolICMP, hcloudsdk.FirewallRuleProtocolESP, hcloudsdk.FirewallRuleProtocolGRE:
+		if f.Port != nil {
+			return fmt.Errorf("%s rules cannot have a port", f.Protocol)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported firewall protocol %q", f.Protocol)
+	}
+}
+
 // Allow more explicit control of the port
+// +kubebuilder:validation:XValidation:rule="!has(self.start) || !has(self.end) || self.start <= self.end",message="end must not be lower than start"
 type FirewallPort struct {
 	// +kubebuilder:validation:Optional
 	All bool `json:"all"`
 
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
 	Start *int `json:"start,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
 	End *int `json:"end,omitempty"`
 }
 
+// MaxFirewallPort is the highest port a firewall rule can match.
+const MaxFirewallPort = 65535
+
+// Validate checks the port is all ports or a range within 1-65535.
+func (f *FirewallPort) Validate() error {
+	if f.All {
+		if f.Start != nil || f.End != nil {
+			return fmt.Errorf("start and end cannot be set with all")
+		}
+		return nil
+	}
+	if f.Start == nil {
+		return fmt.Errorf("a port is required unless all is set")
+	}
+	if *f.Start < 1 || *f.Start > MaxFirewallPort {
+		return fmt.Errorf("port %d must be between 1 and %d", *f.Start, MaxFirewallPort)
+	}
+	if f.End != nil {
+		if *f.End < 1 || *f.End > MaxFirewallPort {
+			return fmt.Errorf("port %d must be between 1 and %d", *f.End, MaxFirewallPort)
+		}
+		if *f.End < *f.Start {
+			return fmt.Errorf("port range %d-%d ends before it starts", *f.Start, *f.End)
+		}
+	}
+	return nil
+}
+
 func (f *FirewallPort) String() (s string) {
 	if f == nil || f.All {
 		s = "any"
@@ -166,9 +323,16 @@ type FirewallObservation struct {
 }
 
 // A FirewallSpec defines the desired state of a Firewall.
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
 type FirewallSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       FirewallParameters `json:"forProvider"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	ForProvider FirewallParameters `json:"forProvider"`
 }
 
 // A FirewallStatus represents the observed state of a Firewall.
@@ -185,6 +349,7 @@ type FirewallStatus struct {
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
 type Firewall struct {
 	metav1.TypeMeta   `json:",inline"`
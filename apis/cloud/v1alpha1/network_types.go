@@ -24,6 +24,8 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
 type NetworkRoute struct {
@@ -51,7 +53,7 @@ type NetworkParameters struct {
 	Routes []NetworkRoute `json:"routes"`
 
 	// +kubebuilder:validation:Optional
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
 
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:Optional
@@ -68,9 +70,16 @@ type NetworkObservation struct {
 }
 
 // A NetworkSpec defines the desired state of a Network.
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
 type NetworkSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       NetworkParameters `json:"forProvider"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	ForProvider NetworkParameters `json:"forProvider"`
 }
 
 // A NetworkStatus represents the observed state of a Network.
@@ -87,6 +96,7 @@ type NetworkStatus struct {
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
 type Network struct {
 	metav1.TypeMeta   `json:",inline"`
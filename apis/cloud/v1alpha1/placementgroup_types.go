@@ -24,12 +24,14 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
 // PlacementGroupParameters are the configurable fields of a PlacementGroup.
 type PlacementGroupParameters struct {
 	// +kubebuilder:validation:Optional
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
 
 	// +kubebuilder:default:=spread
 	// +kubebuilder:validation:Enum:=spread
@@ -42,14 +44,33 @@ type PlacementGroupObservation struct {
 	// +kubebuilder:validation:Optional
 	ID int64 `json:"id"`
 
+	// Servers are the IDs of the servers in the placement group
+	// +kubebuilder:validation:Optional
+	Servers []int64 `json:"servers,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	*PlacementGroupParameters `json:"params,omitempty"`
 }
 
 // A PlacementGroupSpec defines the desired state of a PlacementGroup.
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.type == oldSelf.forProvider.type",message="forProvider.type cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
 type PlacementGroupSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       PlacementGroupParameters `json:"forProvider"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	// ReplacementPolicy decides what happens when a create-only field of
+	// forProvider is changed. Never rejects the change, Replace deletes and
+	// recreates the Hetzner resource.
+	// +kubebuilder:default:=Never
+	// +kubebuilder:validation:Optional
+	ReplacementPolicy apisv1alpha1.ReplacementPolicy `json:"replacementPolicy,omitempty"`
+
+	ForProvider PlacementGroupParameters `json:"forProvider"`
 }
 
 // A PlacementGroupStatus represents the observed state of a PlacementGroup.
@@ -66,6 +87,7 @@ type PlacementGroupStatus struct {
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
 type PlacementGroup struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -90,6 +112,20 @@ func (p *PlacementGroup) IsUpToDate() bool {
 	return true
 }
 
+// NeedsReplacement reports whether a create-only field has changed since the
+// Hetzner resource was created, and the replacement policy allows it to be
+// recreated.
+func (p *PlacementGroup) NeedsReplacement() bool {
+	target := p.Spec.ForProvider
+	current := p.Status.AtProvider.PlacementGroupParameters
+
+	if current == nil || p.Spec.ReplacementPolicy != apisv1alpha1.ReplacementPolicyReplace {
+		return false
+	}
+
+	return target.Type != current.Type
+}
+
 // +kubebuilder:object:root=true
 
 // PlacementGroupList contains a list of PlacementGroup
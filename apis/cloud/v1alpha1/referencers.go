@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HetznerID extracts the Hetzner ID of a referenced managed resource. Nothing
+// is extracted until the resource has been created.
+func HetznerID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		var id int64
+
+		switch cr := mg.(type) {
+		case *Firewall:
+			id = cr.Status.AtProvider.ID
+		case *Network:
+			id = cr.Status.AtProvider.ID
+		case *PlacementGroup:
+			id = cr.Status.AtProvider.ID
+		case *Server:
+			id = cr.Status.AtProvider.ID
+		case *Volume:
+			id = cr.Status.AtProvider.ID
+		}
+
+		if id == 0 {
+			return ""
+		}
+		return strconv.FormatInt(id, 10)
+	}
+}
+
+// ResolveReferences of this Server
+func (mg *Server) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+	p := &mg.Spec.ForProvider
+
+	rsp, err := r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+		CurrentValues: fromIDs(p.FirewallIDs),
+		References:    p.FirewallIDRefs,
+		Selector:      p.FirewallIDSelector,
+		To:            reference.To{Managed: &Firewall{}, List: &FirewallList{}},
+		Extract:       HetznerID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.firewallIDs")
+	}
+	p.FirewallIDs = toIDs(rsp.ResolvedValues)
+	p.FirewallIDRefs = rsp.ResolvedReferences
+
+	rsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+		CurrentValues: fromIDs(p.NetworkIDs),
+		References:    p.NetworkIDRefs,
+		Selector:      p.NetworkIDSelector,
+		To:            reference.To{Managed: &Network{}, List: &NetworkList{}},
+		Extract:       HetznerID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.networkIDs")
+	}
+	p.NetworkIDs = toIDs(rsp.ResolvedValues)
+	p.NetworkIDRefs = rsp.ResolvedReferences
+
+	single, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromIntPtrValue(p.PlacementGroupID),
+		Reference:    p.PlacementGroupIDRef,
+		Selector:     p.PlacementGroupIDSelector,
+		To:           reference.To{Managed: &PlacementGroup{}, List: &PlacementGroupList{}},
+		Extract:      HetznerID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.placementGroupID")
+	}
+	p.PlacementGroupID = reference.ToIntPtrValue(single.ResolvedValue)
+	p.PlacementGroupIDRef = single.ResolvedReference
+
+	rsp, err = r.ResolveMultiple(ctx, reference.MultiResolutionRequest{
+		CurrentValues: fromIDs(p.VolumeIDs),
+		References:    p.VolumeIDRefs,
+		Selector:      p.VolumeIDSelector,
+		To:            reference.To{Managed: &Volume{}, List: &VolumeList{}},
+		Extract:       HetznerID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.volumeIDs")
+	}
+	p.VolumeIDs = toIDs(rsp.ResolvedValues)
+	p.VolumeIDRefs = rsp.ResolvedReferences
+
+	return nil
+}
+
+// ResolveReferences of this Volume
+func (mg *Volume) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+	p := &mg.Spec.ForProvider
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromIntPtrValue(p.ServerID),
+		Reference:    p.ServerIDRef,
+		Selector:     p.ServerIDSelector,
+		To:           reference.To{Managed: &Server{}, List: &ServerList{}},
+		Extract:      HetznerID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.serverID")
+	}
+	p.ServerID = reference.ToIntPtrValue(rsp.ResolvedValue)
+	p.ServerIDRef = rsp.ResolvedReference
+
+	return nil
+}
+
+func fromIDs(ids []int64) []string {
+	if ids == nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.FormatInt(id, 10))
+	}
+	return values
+}
+
+func toIDs(values []string) []int64 {
+	if values == nil {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		if id := reference.ToIntPtrValue(v); id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
@@ -23,6 +23,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
 // VolumeParameters are the configurable fields of a Volume.
@@ -38,13 +41,21 @@ type VolumeParameters struct {
 	Format string `json:"format"`
 
 	// +kubebuilder:validation:Optional
-	Labels map[string]string `json:"labels,omitempty"`
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	Location *string `json:"location,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	ServerID *int64 `json:"serverID"`
+
+	// ServerIDRef is a reference to a Server used to set ServerID
+	// +kubebuilder:validation:Optional
+	ServerIDRef *xpv1.Reference `json:"serverIDRef,omitempty"`
+
+	// ServerIDSelector selects a reference to a Server used to set ServerID
+	// +kubebuilder:validation:Optional
+	ServerIDSelector *xpv1.Selector `json:"serverIDSelector,omitempty"`
 }
 
 // VolumeObservation are the observable fields of a Volume.
@@ -52,14 +63,37 @@ type VolumeObservation struct {
 	// +kubebuilder:validation:Optional
 	ID int64 `json:"id"`
 
+	// +kubebuilder:validation:Optional
+	Status hcloud.VolumeStatus `json:"status,omitempty"`
+
+	// LinuxDevice is the path of the volume on the server it is attached to
+	// +kubebuilder:validation:Optional
+	LinuxDevice string `json:"linuxDevice,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	*VolumeParameters `json:"params,omitempty"`
 }
 
 // A VolumeSpec defines the desired state of a Volume.
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.format == oldSelf.forProvider.format",message="forProvider.format cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || (has(self.forProvider.location) ? has(oldSelf.forProvider.location) && self.forProvider.location == oldSelf.forProvider.location : !has(oldSelf.forProvider.location))",message="forProvider.location cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
 type VolumeSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
-	ForProvider       VolumeParameters `json:"forProvider"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	// ReplacementPolicy decides what happens when a create-only field of
+	// forProvider is changed. Never rejects the change, Replace deletes and
+	// recreates the Hetzner resource.
+	// +kubebuilder:default:=Never
+	// +kubebuilder:validation:Optional
+	ReplacementPolicy apisv1alpha1.ReplacementPolicy `json:"replacementPolicy,omitempty"`
+
+	ForProvider VolumeParameters `json:"forProvider"`
 }
 
 // A VolumeStatus represents the observed state of a Volume.
@@ -76,6 +110,7 @@ type VolumeStatus struct {
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
 type Volume struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -108,6 +143,21 @@ func (v *Volume) IsUpToDate() bool {
 	return true
 }
 
+// NeedsReplacement reports whether a create-only field has changed since the
+// Hetzner resource was created, and the replacement policy allows it to be
+// recreated.
+func (v *Volume) NeedsReplacement() bool {
+	target := v.Spec.ForProvider
+	current := v.Status.AtProvider.VolumeParameters
+
+	if current == nil || v.Spec.ReplacementPolicy != apisv1alpha1.ReplacementPolicyReplace {
+		return false
+	}
+
+	return target.Format != current.Format ||
+		!reflect.DeepEqual(target.Location, current.Location)
+}
+
 // +kubebuilder:object:root=true
 
 // VolumeList contains a list of Volume
@@ -21,6 +21,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -70,6 +73,11 @@ func (in *FirewallApplyTo) DeepCopyInto(out *FirewallApplyTo) {
 			}
 		}
 	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallApplyTo.
@@ -146,7 +154,7 @@ func (in *FirewallParameters) DeepCopyInto(out *FirewallParameters) {
 	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+		*out = make(apisv1alpha1.Labels, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -198,6 +206,16 @@ func (in *FirewallPort) DeepCopy() *FirewallPort {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FirewallRules) DeepCopyInto(out *FirewallRules) {
 	*out = *in
+	if in.SourceIPs != nil {
+		in, out := &in.SourceIPs, &out.SourceIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationIPs != nil {
+		in, out := &in.DestinationIPs, &out.DestinationIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.TargetIPs != nil {
 		in, out := &in.TargetIPs, &out.TargetIPs
 		*out = make([]string, len(*in))
@@ -229,6 +247,11 @@ func (in *FirewallRules) DeepCopy() *FirewallRules {
 func (in *FirewallSpec) DeepCopyInto(out *FirewallSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -353,7 +376,7 @@ func (in *NetworkParameters) DeepCopyInto(out *NetworkParameters) {
 	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+		*out = make(apisv1alpha1.Labels, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -389,6 +412,11 @@ func (in *NetworkRoute) DeepCopy() *NetworkRoute {
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -496,6 +524,11 @@ func (in *PlacementGroupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlacementGroupObservation) DeepCopyInto(out *PlacementGroupObservation) {
 	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
 	if in.PlacementGroupParameters != nil {
 		in, out := &in.PlacementGroupParameters, &out.PlacementGroupParameters
 		*out = new(PlacementGroupParameters)
@@ -518,7 +551,7 @@ func (in *PlacementGroupParameters) DeepCopyInto(out *PlacementGroupParameters)
 	*out = *in
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+		*out = make(apisv1alpha1.Labels, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -539,6 +572,11 @@ func (in *PlacementGroupParameters) DeepCopy() *PlacementGroupParameters {
 func (in *PlacementGroupSpec) DeepCopyInto(out *PlacementGroupSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -631,6 +669,19 @@ func (in *ServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerObservation) DeepCopyInto(out *ServerObservation) {
 	*out = *in
+	if in.NextPowerTransition != nil {
+		in, out := &in.NextPowerTransition, &out.NextPowerTransition
+		*out = (*in).DeepCopy()
+	}
+	if in.ShutdownRequestedAt != nil {
+		in, out := &in.ShutdownRequestedAt, &out.ShutdownRequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(apisv1alpha1.OperationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServerParameters != nil {
 		in, out := &in.ServerParameters, &out.ServerParameters
 		*out = new(ServerParameters)
@@ -666,9 +717,26 @@ func (in *ServerParameters) DeepCopyInto(out *ServerParameters) {
 		*out = make([]int64, len(*in))
 		copy(*out, *in)
 	}
+	if in.FirewallIDRefs != nil {
+		in, out := &in.FirewallIDRefs, &out.FirewallIDRefs
+		*out = make([]commonv1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FirewallIDSelector != nil {
+		in, out := &in.FirewallIDSelector, &out.FirewallIDSelector
+		*out = new(commonv1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ISO != nil {
+		in, out := &in.ISO, &out.ISO
+		*out = new(string)
+		**out = **in
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+		*out = make(apisv1alpha1.Labels, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -678,21 +746,77 @@ func (in *ServerParameters) DeepCopyInto(out *ServerParameters) {
 		*out = make([]int64, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkIDRefs != nil {
+		in, out := &in.NetworkIDRefs, &out.NetworkIDRefs
+		*out = make([]commonv1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkIDSelector != nil {
+		in, out := &in.NetworkIDSelector, &out.NetworkIDSelector
+		*out = new(commonv1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PlacementGroupID != nil {
 		in, out := &in.PlacementGroupID, &out.PlacementGroupID
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PlacementGroupIDRef != nil {
+		in, out := &in.PlacementGroupIDRef, &out.PlacementGroupIDRef
+		*out = new(commonv1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlacementGroupIDSelector != nil {
+		in, out := &in.PlacementGroupIDSelector, &out.PlacementGroupIDSelector
+		*out = new(commonv1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(apisv1alpha1.PowerSchedule)
+		**out = **in
+	}
+	if in.PowerOff != nil {
+		in, out := &in.PowerOff, &out.PowerOff
+		*out = new(apisv1alpha1.PowerOffPolicy)
+		**out = **in
+	}
 	if in.SSHKeys != nil {
 		in, out := &in.SSHKeys, &out.SSHKeys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(apisv1alpha1.ReadinessProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserDataFrom != nil {
+		in, out := &in.UserDataFrom, &out.UserDataFrom
+		*out = make([]apisv1alpha1.UserDataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.VolumeIDs != nil {
 		in, out := &in.VolumeIDs, &out.VolumeIDs
 		*out = make([]int64, len(*in))
 		copy(*out, *in)
 	}
+	if in.VolumeIDRefs != nil {
+		in, out := &in.VolumeIDRefs, &out.VolumeIDRefs
+		*out = make([]commonv1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeIDSelector != nil {
+		in, out := &in.VolumeIDSelector, &out.VolumeIDSelector
+		*out = new(commonv1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerParameters.
@@ -705,10 +829,149 @@ func (in *ServerParameters) DeepCopy() *ServerParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPool) DeepCopyInto(out *ServerPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPool.
+func (in *ServerPool) DeepCopy() *ServerPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServerPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPoolList) DeepCopyInto(out *ServerPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServerPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPoolList.
+func (in *ServerPoolList) DeepCopy() *ServerPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServerPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPoolObservation) DeepCopyInto(out *ServerPoolObservation) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPoolObservation.
+func (in *ServerPoolObservation) DeepCopy() *ServerPoolObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPoolObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPoolParameters) DeepCopyInto(out *ServerPoolParameters) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPoolParameters.
+func (in *ServerPoolParameters) DeepCopy() *ServerPoolParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPoolParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPoolSpec) DeepCopyInto(out *ServerPoolSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPoolSpec.
+func (in *ServerPoolSpec) DeepCopy() *ServerPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerPoolStatus) DeepCopyInto(out *ServerPoolStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerPoolStatus.
+func (in *ServerPoolStatus) DeepCopy() *ServerPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
@@ -739,6 +1002,27 @@ func (in *ServerStatus) DeepCopy() *ServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerTemplate) DeepCopyInto(out *ServerTemplate) {
+	*out = *in
+	if in.WriteConnectionSecretsToNamespace != nil {
+		in, out := &in.WriteConnectionSecretsToNamespace, &out.WriteConnectionSecretsToNamespace
+		*out = new(string)
+		**out = **in
+	}
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerTemplate.
+func (in *ServerTemplate) DeepCopy() *ServerTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
@@ -823,7 +1107,7 @@ func (in *VolumeParameters) DeepCopyInto(out *VolumeParameters) {
 	*out = *in
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
+		*out = make(apisv1alpha1.Labels, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -838,6 +1122,16 @@ func (in *VolumeParameters) DeepCopyInto(out *VolumeParameters) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ServerIDRef != nil {
+		in, out := &in.ServerIDRef, &out.ServerIDRef
+		*out = new(commonv1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerIDSelector != nil {
+		in, out := &in.ServerIDSelector, &out.ServerIDSelector
+		*out = new(commonv1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeParameters.
@@ -854,6 +1148,11 @@ func (in *VolumeParameters) DeepCopy() *VolumeParameters {
 func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(string)
+		**out = **in
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
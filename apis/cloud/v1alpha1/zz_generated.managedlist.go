@@ -55,6 +55,15 @@ func (l *ServerList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this ServerPoolList.
+func (l *ServerPoolList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this VolumeList.
 func (l *VolumeList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
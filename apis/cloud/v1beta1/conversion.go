@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionDataAnnotation holds the parameters a v1alpha1 resource was last
+// reconciled with. v1beta1 does not repeat them in its status, so they are
+// kept here to make converting back to v1alpha1 lossless.
+const ConversionDataAnnotation = "cloud.hetzner.crossplane.io/conversion-data"
+
+const (
+	errNotHub              = "object is not a v1alpha1 %s"
+	errMarshalConversion   = "cannot marshal conversion data"
+	errUnmarshalConversion = "cannot unmarshal conversion data"
+)
+
+// saveConversionData records data in the conversion data annotation
+func saveConversionData(obj metav1.Object, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, errMarshalConversion)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConversionDataAnnotation] = string(b)
+	obj.SetAnnotations(annotations)
+
+	return nil
+}
+
+// restoreConversionData reads the conversion data annotation into data, and
+// removes the annotation. data is left untouched if there is no annotation.
+func restoreConversionData(obj metav1.Object, data any) error {
+	annotations := obj.GetAnnotations()
+
+	v, ok := annotations[ConversionDataAnnotation]
+	if !ok {
+		return nil
+	}
+
+	delete(annotations, ConversionDataAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	return errors.Wrap(json.Unmarshal([]byte(v), data), errUnmarshalConversion)
+}
+
+// convertSlice converts each element of a slice. nil and empty slices are kept
+// distinct so that a round trip does not change the object.
+func convertSlice[S, T any](s []S, convert func(S) T) []T {
+	if s == nil {
+		return nil
+	}
+
+	t := make([]T, 0, len(s))
+	for _, v := range s {
+		t = append(t, convert(v))
+	}
+	return t
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+func TestRoundTrip(t *testing.T) {
+	meta := metav1.ObjectMeta{
+		Name:        "example",
+		Annotations: map[string]string{"crossplane.io/external-name": "example"},
+	}
+
+	firewallParams := v1alpha1.FirewallParameters{
+		ApplyTo: []v1alpha1.FirewallApplyTo{
+			{Type: hcloudsdk.FirewallResourceTypeServer, ServerID: hcloudsdk.Ptr[int64](1)},
+		},
+		Labels: apisv1alpha1.Labels{"env": "test"},
+		Rules: []v1alpha1.FirewallRules{
+			{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				TargetIPs: []string{"0.0.0.0/0"},
+				Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(80), End: hcloudsdk.Ptr(443)},
+			},
+			{
+				Direction:      hcloudsdk.FirewallRuleDirectionOut,
+				Protocol:       hcloudsdk.FirewallRuleProtocolICMP,
+				DestinationIPs: []string{v1alpha1.FirewallAnyIPv4, "fd00::/8"},
+			},
+		},
+	}
+	networkParams := v1alpha1.NetworkParameters{
+		IPRange: "10.0.0.0/16",
+		Subnets: []v1alpha1.NetworkSubnet{
+			{Type: hcloudsdk.NetworkSubnetTypeVSwitch, IPRange: "10.0.1.0/24", NetworkZone: hcloudsdk.NetworkZoneEUCentral, VSwitchID: 42},
+		},
+		Routes:                []v1alpha1.NetworkRoute{},
+		ExposeRoutesToVSwitch: true,
+	}
+	placementGroupParams := v1alpha1.PlacementGroupParameters{Type: hcloudsdk.PlacementGroupTypeSpread}
+	serverParams := v1alpha1.ServerParameters{
+		Image:           "ubuntu-22.04",
+		ServerType:      "cx22",
+		Location:        hcloudsdk.Ptr("fsn1"),
+		FirewallIDs:     []int64{1},
+		FirewallIDRefs:  []xpv1.Reference{{Name: "firewall"}},
+		ISO:             hcloudsdk.Ptr("ubuntu-24.04-live-server-amd64.iso"),
+		PowerOn:         true,
+		PowerSchedule:   &apisv1alpha1.PowerSchedule{On: "0 8 * * 1-5", Off: "0 20 * * 1-5", TimeZone: "Europe/Berlin"},
+		PowerOff:        &apisv1alpha1.PowerOffPolicy{Method: apisv1alpha1.PowerOffMethodShutdown, ShutdownTimeoutSeconds: 120},
+		GenerateSSHKey:  true,
+		GenerateHostKey: true,
+	}
+	volumeParams := v1alpha1.VolumeParameters{
+		Size:        10,
+		Automount:   true,
+		Format:      "ext4",
+		ServerID:    hcloudsdk.Ptr[int64](1),
+		ServerIDRef: &xpv1.Reference{Name: "server"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		hub    conversion.Hub
+		spoke  conversion.Convertible
+	}{
+		"Firewall": {
+			reason: "A Firewall should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.Firewall{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.FirewallSpec{ForProvider: firewallParams},
+				Status: v1alpha1.FirewallStatus{
+					AtProvider: v1alpha1.FirewallObservation{ID: 42, FirewallParameters: &firewallParams},
+				},
+			},
+			spoke: &Firewall{},
+		},
+		"Network": {
+			reason: "A Network should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.Network{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.NetworkSpec{ForProvider: networkParams},
+				Status: v1alpha1.NetworkStatus{
+					AtProvider: v1alpha1.NetworkObservation{ID: 42, NetworkParameters: &networkParams},
+				},
+			},
+			spoke: &Network{},
+		},
+		"PlacementGroup": {
+			reason: "A PlacementGroup should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.PlacementGroup{
+				ObjectMeta: meta,
+				Spec: v1alpha1.PlacementGroupSpec{
+					ReplacementPolicy: apisv1alpha1.ReplacementPolicyReplace,
+					ForProvider:       placementGroupParams,
+				},
+				Status: v1alpha1.PlacementGroupStatus{
+					AtProvider: v1alpha1.PlacementGroupObservation{ID: 42, Servers: []int64{1, 2}, PlacementGroupParameters: &placementGroupParams},
+				},
+			},
+			spoke: &PlacementGroup{},
+		},
+		"Server": {
+			reason: "A Server should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.Server{
+				ObjectMeta: meta,
+				Spec: v1alpha1.ServerSpec{
+					Project:     hcloudsdk.Ptr("production"),
+					ForProvider: serverParams,
+				},
+				Status: v1alpha1.ServerStatus{
+					AtProvider: v1alpha1.ServerObservation{
+						ID:                  42,
+						Status:              hcloudsdk.ServerStatusRunning,
+						PublicIPv4:          "192.0.2.1",
+						GeneratedSSHKeyID:   7,
+						HostKey:             "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHostKey web",
+						AttachedISO:         "ubuntu-24.04-live-server-amd64.iso",
+						NextPowerTransition: &metav1.Time{Time: time.Date(2024, time.July, 3, 18, 0, 0, 0, time.UTC)},
+						LastOperation: &apisv1alpha1.OperationStatus{
+							Operation: apisv1alpha1.ServerOperationReboot,
+							Result:    apisv1alpha1.OperationSucceeded,
+							Time:      metav1.Time{Time: time.Date(2024, time.July, 3, 12, 0, 0, 0, time.UTC)},
+						},
+						ServerParameters: &serverParams,
+					},
+				},
+			},
+			spoke: &Server{},
+		},
+		"ServerPool": {
+			reason: "A ServerPool should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.ServerPool{
+				ObjectMeta: meta,
+				Spec: v1alpha1.ServerPoolSpec{
+					Project: hcloudsdk.Ptr("production"),
+					ForProvider: v1alpha1.ServerPoolParameters{
+						Replicas:       3,
+						NamePattern:    "{pool}-{index}",
+						Spread:         true,
+						MaxUnavailable: 1,
+						Template: v1alpha1.ServerTemplate{
+							WriteConnectionSecretsToNamespace: hcloudsdk.Ptr("default"),
+							ForProvider:                       serverParams,
+						},
+					},
+				},
+				Status: v1alpha1.ServerPoolStatus{
+					AtProvider: v1alpha1.ServerPoolObservation{
+						Replicas:          3,
+						ReadyReplicas:     2,
+						UpdatedReplicas:   3,
+						AvailableReplicas: 2,
+						Servers:           []string{"example-0", "example-1", "example-2"},
+					},
+				},
+			},
+			spoke: &ServerPool{},
+		},
+		"ServerNotCreated": {
+			reason: "A Server that has not been created should not gain a conversion data annotation",
+			hub: &v1alpha1.Server{
+				Spec: v1alpha1.ServerSpec{ForProvider: serverParams},
+			},
+			spoke: &Server{},
+		},
+		"Volume": {
+			reason: "A Volume should survive conversion to v1beta1 and back",
+			hub: &v1alpha1.Volume{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.VolumeSpec{ForProvider: volumeParams},
+				Status: v1alpha1.VolumeStatus{
+					AtProvider: v1alpha1.VolumeObservation{ID: 42, LinuxDevice: "/dev/disk/by-id/scsi-0HC_Volume_42", VolumeParameters: &volumeParams},
+				},
+			},
+			spoke: &Volume{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.spoke.ConvertFrom(tc.hub); err != nil {
+				t.Fatalf("\n%s\nConvertFrom(...): %s", tc.reason, err)
+			}
+
+			got := tc.hub.DeepCopyObject().(conversion.Hub)
+			if err := tc.spoke.ConvertTo(got); err != nil {
+				t.Fatalf("\n%s\nConvertTo(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.hub, got); diff != "" {
+				t.Errorf("\n%s\nConvertTo(ConvertFrom(...)): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConvertFromServer(t *testing.T) {
+	params := v1alpha1.ServerParameters{Image: "ubuntu-22.04"}
+	hub := &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       v1alpha1.ServerSpec{ForProvider: params},
+		Status: v1alpha1.ServerStatus{
+			AtProvider: v1alpha1.ServerObservation{ID: 42, ServerParameters: &params},
+		},
+	}
+
+	got := &Server{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom(...): %s", err)
+	}
+
+	want := &Server{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "example",
+			Annotations: map[string]string{
+				ConversionDataAnnotation: `{"image":"ubuntu-22.04","serverType":"","architecture":"","autoMount":false,"enableIPv4":false,"enableIPv6":false,"firewallIDs":null,"networkIDs":null,"powerOn":false,"sshKeys":null,"startAfterCreate":false,"userData":"","volumeIDs":null}`,
+			},
+		},
+		Spec:   ServerSpec{ForProvider: ServerParameters{Image: "ubuntu-22.04"}},
+		Status: ServerStatus{AtProvider: ServerObservation{ID: 42}},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ConvertFrom(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains the v1beta1 group cloud resources of the Hetzner
+// provider. The resources are stored as v1alpha1 and converted on request.
+// +kubebuilder:object:generate=true
+// +groupName=cloud.hetzner.crossplane.io
+// +versionName=v1beta1
+package v1beta1
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this Firewall to the v1alpha1 hub.
+func (src *Firewall) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Firewall)
+	if !ok {
+		return errors.Errorf(errNotHub, FirewallKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.FirewallSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: v1alpha1.FirewallParameters{
+			ApplyTo: convertSlice(p.ApplyTo, func(a FirewallApplyTo) v1alpha1.FirewallApplyTo {
+				return v1alpha1.FirewallApplyTo(a)
+			}),
+			Labels: p.Labels,
+			Rules: convertSlice(p.Rules, func(r FirewallRule) v1alpha1.FirewallRules {
+				return v1alpha1.FirewallRules{
+					Direction:      r.Direction,
+					Protocol:       r.Protocol,
+					SourceIPs:      r.SourceIPs,
+					DestinationIPs: r.DestinationIPs,
+					TargetIPs:      r.TargetIPs,
+					Description:    r.Description,
+					Port:           (*v1alpha1.FirewallPort)(r.Port),
+				}
+			}),
+		},
+	}
+	dst.Status = v1alpha1.FirewallStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: v1alpha1.FirewallObservation{
+			ID: s.Status.AtProvider.ID,
+		},
+	}
+
+	return restoreConversionData(dst, &dst.Status.AtProvider.FirewallParameters)
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Firewall.
+func (dst *Firewall) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Firewall)
+	if !ok {
+		return errors.Errorf(errNotHub, FirewallKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = FirewallSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: FirewallParameters{
+			ApplyTo: convertSlice(p.ApplyTo, func(a v1alpha1.FirewallApplyTo) FirewallApplyTo {
+				return FirewallApplyTo(a)
+			}),
+			Labels: p.Labels,
+			Rules: convertSlice(p.Rules, func(r v1alpha1.FirewallRules) FirewallRule {
+				return FirewallRule{
+					Direction:      r.Direction,
+					Protocol:       r.Protocol,
+					SourceIPs:      r.SourceIPs,
+					DestinationIPs: r.DestinationIPs,
+					TargetIPs:      r.TargetIPs,
+					Description:    r.Description,
+					Port:           (*FirewallPort)(r.Port),
+				}
+			}),
+		},
+	}
+	dst.Status = FirewallStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: FirewallObservation{
+			ID: s.Status.AtProvider.ID,
+		},
+	}
+
+	if params := s.Status.AtProvider.FirewallParameters; params != nil {
+		return saveConversionData(dst, params)
+	}
+	return nil
+}
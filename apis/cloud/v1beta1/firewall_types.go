@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// FirewallParameters are the configurable fields of a Firewall.
+type FirewallParameters struct {
+	// +kubebuilder:validation:Optional
+	ApplyTo []FirewallApplyTo `json:"applyTo,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Rules []FirewallRule `json:"rules,omitempty"`
+}
+
+// A FirewallApplyTo is a server or a set of labelled servers the firewall is
+// applied to.
+// +kubebuilder:validation:XValidation:rule="self.type != 'label_selector' || has(self.labels) || has(self.labelSelector)",message="labels or labelSelector is required for the label_selector type"
+// +kubebuilder:validation:XValidation:rule="!(has(self.labels) && has(self.labelSelector))",message="only one of labels or labelSelector may be set"
+type FirewallApplyTo struct {
+	// +kubebuilder:validation:Enum:=server;label_selector
+	Type hcloudsdk.FirewallResourceType `json:"type"`
+
+	// +kubebuilder:validation:Optional
+	ServerID *int64 `json:"serverID,omitempty"`
+
+	// Labels matches resources with all of the given labels. Use
+	// labelSelector for anything other than equality.
+	// +kubebuilder:validation:Optional
+	Labels *map[string]string `json:"labels,omitempty"`
+
+	// LabelSelector matches resources using Kubernetes label selector
+	// semantics, translated to the Hetzner label selector syntax.
+	// +kubebuilder:validation:Optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// A FirewallRule allows traffic in or out of the servers the firewall is
+// applied to.
+// +kubebuilder:validation:XValidation:rule="self.direction != 'in' || !has(self.destinationIPs) || size(self.destinationIPs) == 0",message="destinationIPs cannot be set for in rules"
+// +kubebuilder:validation:XValidation:rule="self.direction != 'out' || !has(self.sourceIPs) || size(self.sourceIPs) == 0",message="sourceIPs cannot be set for out rules"
+type FirewallRule struct {
+	// +kubebuilder:validation:Enum:=in;out
+	Direction hcloudsdk.FirewallRuleDirection `json:"direction"`
+
+	// +kubebuilder:validation:Enum:=tcp;udp;icmp;esp;gre
+	Protocol hcloudsdk.FirewallRuleProtocol `json:"protocol"`
+
+	// SourceIPs are the CIDRs traffic of an in rule is allowed from. Use
+	// any, any-ipv4 or any-ipv6 to match every address.
+	// +kubebuilder:validation:Optional
+	SourceIPs []string `json:"sourceIPs,omitempty"`
+
+	// DestinationIPs are the CIDRs traffic of an out rule is allowed to.
+	// Use any, any-ipv4 or any-ipv6 to match every address.
+	// +kubebuilder:validation:Optional
+	DestinationIPs []string `json:"destinationIPs,omitempty"`
+
+	// TargetIPs are the source IPs of an in rule or the destination IPs of
+	// an out rule.
+	// Deprecated: use sourceIPs or destinationIPs.
+	// +kubebuilder:validation:Optional
+	TargetIPs []string `json:"targetIPs,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Description *string `json:"description,omitempty"`
+
+	// Port is the port or port range of a tcp or udp rule. Leaving it out
+	// matches every port, as all: true does. It cannot be set for any other
+	// protocol.
+	// +kubebuilder:validation:Optional
+	Port *FirewallPort `json:"port,omitempty"`
+}
+
+// A FirewallPort is a single port, a range of ports or all ports.
+// +kubebuilder:validation:XValidation:rule="!has(self.start) || !has(self.end) || self.start <= self.end",message="end must not be lower than start"
+type FirewallPort struct {
+	// +kubebuilder:validation:Optional
+	All bool `json:"all,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	Start *int `json:"start,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	End *int `json:"end,omitempty"`
+}
+
+// FirewallObservation are the observable fields of a Firewall.
+type FirewallObservation struct {
+	// +kubebuilder:validation:Optional
+	ID int64 `json:"id,omitempty"`
+}
+
+// A FirewallSpec defines the desired state of a Firewall.
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type FirewallSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	ForProvider FirewallParameters `json:"forProvider"`
+}
+
+// A FirewallStatus represents the observed state of a Firewall.
+type FirewallStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          FirewallObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Firewall is a Hetzner Cloud firewall.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type Firewall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FirewallSpec   `json:"spec"`
+	Status FirewallStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FirewallList contains a list of Firewall
+type FirewallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Firewall `json:"items"`
+}
+
+// Firewall type metadata.
+var (
+	FirewallKind             = reflect.TypeOf(Firewall{}).Name()
+	FirewallGroupKind        = schema.GroupKind{Group: Group, Kind: FirewallKind}.String()
+	FirewallKindAPIVersion   = FirewallKind + "." + SchemeGroupVersion.String()
+	FirewallGroupVersionKind = SchemeGroupVersion.WithKind(FirewallKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Firewall{}, &FirewallList{})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this Network to the v1alpha1 hub.
+func (src *Network) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Network)
+	if !ok {
+		return errors.Errorf(errNotHub, NetworkKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.NetworkSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: v1alpha1.NetworkParameters{
+			IPRange: p.IPRange,
+			Subnets: convertSlice(p.Subnets, func(s NetworkSubnet) v1alpha1.NetworkSubnet {
+				return v1alpha1.NetworkSubnet(s)
+			}),
+			Routes: convertSlice(p.Routes, func(r NetworkRoute) v1alpha1.NetworkRoute {
+				return v1alpha1.NetworkRoute(r)
+			}),
+			Labels:                p.Labels,
+			ExposeRoutesToVSwitch: p.ExposeRoutesToVSwitch,
+		},
+	}
+	dst.Status = v1alpha1.NetworkStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: v1alpha1.NetworkObservation{
+			ID: s.Status.AtProvider.ID,
+		},
+	}
+
+	return restoreConversionData(dst, &dst.Status.AtProvider.NetworkParameters)
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Network.
+func (dst *Network) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Network)
+	if !ok {
+		return errors.Errorf(errNotHub, NetworkKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = NetworkSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: NetworkParameters{
+			IPRange: p.IPRange,
+			Subnets: convertSlice(p.Subnets, func(s v1alpha1.NetworkSubnet) NetworkSubnet {
+				return NetworkSubnet(s)
+			}),
+			Routes: convertSlice(p.Routes, func(r v1alpha1.NetworkRoute) NetworkRoute {
+				return NetworkRoute(r)
+			}),
+			Labels:                p.Labels,
+			ExposeRoutesToVSwitch: p.ExposeRoutesToVSwitch,
+		},
+	}
+	dst.Status = NetworkStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: NetworkObservation{
+			ID: s.Status.AtProvider.ID,
+		},
+	}
+
+	if params := s.Status.AtProvider.NetworkParameters; params != nil {
+		return saveConversionData(dst, params)
+	}
+	return nil
+}
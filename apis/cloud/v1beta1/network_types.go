@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// A NetworkRoute sends traffic for a destination through a gateway server.
+type NetworkRoute struct {
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway"`
+}
+
+// A NetworkSubnet is a range of the network's IPs in a network zone.
+type NetworkSubnet struct {
+	Type        hcloudsdk.NetworkSubnetType `json:"type"`
+	IPRange     string                      `json:"ipRange"`
+	NetworkZone hcloudsdk.NetworkZone       `json:"networkZone"`
+
+	// VSwitchID connects a vswitch subnet to a Robot vSwitch
+	// +kubebuilder:validation:Optional
+	VSwitchID int64 `json:"vSwitchID,omitempty"`
+}
+
+// NetworkParameters are the configurable fields of a Network.
+type NetworkParameters struct {
+	IPRange string `json:"ipRange"`
+
+	// +kubebuilder:validation:MinItems:=1
+	Subnets []NetworkSubnet `json:"subnets"`
+
+	// +kubebuilder:validation:Optional
+	Routes []NetworkRoute `json:"routes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
+
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	ExposeRoutesToVSwitch bool `json:"exposeRoutesToVSwitch"`
+}
+
+// NetworkObservation are the observable fields of a Network.
+type NetworkObservation struct {
+	// +kubebuilder:validation:Optional
+	ID int64 `json:"id,omitempty"`
+}
+
+// A NetworkSpec defines the desired state of a Network.
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type NetworkSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	ForProvider NetworkParameters `json:"forProvider"`
+}
+
+// A NetworkStatus represents the observed state of a Network.
+type NetworkStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          NetworkObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Network is a Hetzner Cloud private network.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type Network struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkSpec   `json:"spec"`
+	Status NetworkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkList contains a list of Network
+type NetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Network `json:"items"`
+}
+
+// Network type metadata.
+var (
+	NetworkKind             = reflect.TypeOf(Network{}).Name()
+	NetworkGroupKind        = schema.GroupKind{Group: Group, Kind: NetworkKind}.String()
+	NetworkKindAPIVersion   = NetworkKind + "." + SchemeGroupVersion.String()
+	NetworkGroupVersionKind = SchemeGroupVersion.WithKind(NetworkKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Network{}, &NetworkList{})
+}
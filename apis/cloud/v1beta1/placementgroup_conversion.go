@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this PlacementGroup to the v1alpha1 hub.
+func (src *PlacementGroup) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.PlacementGroup)
+	if !ok {
+		return errors.Errorf(errNotHub, PlacementGroupKind)
+	}
+	s := src.DeepCopy()
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.PlacementGroupSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		ForProvider:       v1alpha1.PlacementGroupParameters(s.Spec.ForProvider),
+	}
+	dst.Status = v1alpha1.PlacementGroupStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: v1alpha1.PlacementGroupObservation{
+			ID:      s.Status.AtProvider.ID,
+			Servers: s.Status.AtProvider.Servers,
+		},
+	}
+
+	return restoreConversionData(dst, &dst.Status.AtProvider.PlacementGroupParameters)
+}
+
+// ConvertFrom converts the v1alpha1 hub to this PlacementGroup.
+func (dst *PlacementGroup) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.PlacementGroup)
+	if !ok {
+		return errors.Errorf(errNotHub, PlacementGroupKind)
+	}
+	s := src.DeepCopy()
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = PlacementGroupSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		ForProvider:       PlacementGroupParameters(s.Spec.ForProvider),
+	}
+	dst.Status = PlacementGroupStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: PlacementGroupObservation{
+			ID:      s.Status.AtProvider.ID,
+			Servers: s.Status.AtProvider.Servers,
+		},
+	}
+
+	if params := s.Status.AtProvider.PlacementGroupParameters; params != nil {
+		return saveConversionData(dst, params)
+	}
+	return nil
+}
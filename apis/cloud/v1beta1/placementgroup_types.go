@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// PlacementGroupParameters are the configurable fields of a PlacementGroup.
+type PlacementGroupParameters struct {
+	// +kubebuilder:validation:Optional
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
+
+	// +kubebuilder:default:=spread
+	// +kubebuilder:validation:Enum:=spread
+	// +kubebuilder:validation:Optional
+	Type hcloudsdk.PlacementGroupType `json:"type"`
+}
+
+// PlacementGroupObservation are the observable fields of a PlacementGroup.
+type PlacementGroupObservation struct {
+	// +kubebuilder:validation:Optional
+	ID int64 `json:"id,omitempty"`
+
+	// Servers are the IDs of the servers in the placement group
+	// +kubebuilder:validation:Optional
+	Servers []int64 `json:"servers,omitempty"`
+}
+
+// A PlacementGroupSpec defines the desired state of a PlacementGroup.
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.type == oldSelf.forProvider.type",message="forProvider.type cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type PlacementGroupSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	// ReplacementPolicy decides what happens when a create-only field of
+	// forProvider is changed. Never rejects the change, Replace deletes and
+	// recreates the Hetzner resource.
+	// +kubebuilder:default:=Never
+	// +kubebuilder:validation:Optional
+	ReplacementPolicy apisv1alpha1.ReplacementPolicy `json:"replacementPolicy,omitempty"`
+
+	ForProvider PlacementGroupParameters `json:"forProvider"`
+}
+
+// A PlacementGroupStatus represents the observed state of a PlacementGroup.
+type PlacementGroupStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PlacementGroupObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PlacementGroup is a Hetzner Cloud placement group.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type PlacementGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PlacementGroupSpec   `json:"spec"`
+	Status PlacementGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PlacementGroupList contains a list of PlacementGroup
+type PlacementGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementGroup `json:"items"`
+}
+
+// PlacementGroup type metadata.
+var (
+	PlacementGroupKind             = reflect.TypeOf(PlacementGroup{}).Name()
+	PlacementGroupGroupKind        = schema.GroupKind{Group: Group, Kind: PlacementGroupKind}.String()
+	PlacementGroupKindAPIVersion   = PlacementGroupKind + "." + SchemeGroupVersion.String()
+	PlacementGroupGroupVersionKind = SchemeGroupVersion.WithKind(PlacementGroupKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PlacementGroup{}, &PlacementGroupList{})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this Server to the v1alpha1 hub.
+func (src *Server) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Server)
+	if !ok {
+		return errors.Errorf(errNotHub, ServerKind)
+	}
+	s := src.DeepCopy()
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.ServerSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		// The parameters only differ in their JSON names
+		ForProvider: v1alpha1.ServerParameters(s.Spec.ForProvider),
+	}
+	dst.Status = v1alpha1.ServerStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: v1alpha1.ServerObservation{
+			ID:                  s.Status.AtProvider.ID,
+			Status:              s.Status.AtProvider.Status,
+			PublicIPv4:          s.Status.AtProvider.PublicIPv4,
+			PublicIPv6:          s.Status.AtProvider.PublicIPv6,
+			GeneratedSSHKeyID:   s.Status.AtProvider.GeneratedSSHKeyID,
+			HostKey:             s.Status.AtProvider.HostKey,
+			AttachedISO:         s.Status.AtProvider.AttachedISO,
+			NextPowerTransition: s.Status.AtProvider.NextPowerTransition,
+			ShutdownRequestedAt: s.Status.AtProvider.ShutdownRequestedAt,
+			LastOperation:       s.Status.AtProvider.LastOperation,
+		},
+	}
+
+	return restoreConversionData(dst, &dst.Status.AtProvider.ServerParameters)
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Server.
+func (dst *Server) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Server)
+	if !ok {
+		return errors.Errorf(errNotHub, ServerKind)
+	}
+	s := src.DeepCopy()
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = ServerSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		ForProvider:       ServerParameters(s.Spec.ForProvider),
+	}
+	dst.Status = ServerStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: ServerObservation{
+			ID:                  s.Status.AtProvider.ID,
+			Status:              s.Status.AtProvider.Status,
+			PublicIPv4:          s.Status.AtProvider.PublicIPv4,
+			PublicIPv6:          s.Status.AtProvider.PublicIPv6,
+			GeneratedSSHKeyID:   s.Status.AtProvider.GeneratedSSHKeyID,
+			HostKey:             s.Status.AtProvider.HostKey,
+			AttachedISO:         s.Status.AtProvider.AttachedISO,
+			NextPowerTransition: s.Status.AtProvider.NextPowerTransition,
+			ShutdownRequestedAt: s.Status.AtProvider.ShutdownRequestedAt,
+			LastOperation:       s.Status.AtProvider.LastOperation,
+		},
+	}
+
+	if params := s.Status.AtProvider.ServerParameters; params != nil {
+		return saveConversionData(dst, params)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// ServerParameters are the configurable fields of a Server.
+// +kubebuilder:validation:XValidation:rule="has(self.datacenter) != has(self.location)",message="exactly one of datacenter or location is required"
+type ServerParameters struct {
+	Image      string `json:"image"`
+	ServerType string `json:"serverType"`
+
+	// +kubebuilder:validation:Optional
+	Datacenter *string `json:"datacenter,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Location *string `json:"location,omitempty"`
+
+	// +kubebuilder:default:=x86
+	// +kubebuilder:validation:Optional
+	Architecture hcloudsdk.Architecture `json:"architecture"`
+
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	AutoMount bool `json:"autoMount"`
+
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	EnableIPv4 bool `json:"enableIPv4"`
+
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	EnableIPv6 bool `json:"enableIPv6"`
+
+	// +kubebuilder:validation:Optional
+	FirewallIDs []int64 `json:"firewallIDs,omitempty"`
+
+	// FirewallIDRefs are references to Firewalls used to set FirewallIDs
+	// +kubebuilder:validation:Optional
+	FirewallIDRefs []xpv1.Reference `json:"firewallIDRefs,omitempty"`
+
+	// FirewallIDSelector selects references to Firewalls used to set
+	// FirewallIDs
+	// +kubebuilder:validation:Optional
+	FirewallIDSelector *xpv1.Selector `json:"firewallIDSelector,omitempty"`
+
+	// ISO attached to the server, by name or ID. It is detached when
+	// removed, and must be for the server's architecture.
+	// +kubebuilder:validation:Optional
+	ISO *string `json:"iso,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NetworkIDs []int64 `json:"networkIDs,omitempty"`
+
+	// NetworkIDRefs are references to Networks used to set NetworkIDs
+	// +kubebuilder:validation:Optional
+	NetworkIDRefs []xpv1.Reference `json:"networkIDRefs,omitempty"`
+
+	// NetworkIDSelector selects references to Networks used to set
+	// NetworkIDs
+	// +kubebuilder:validation:Optional
+	NetworkIDSelector *xpv1.Selector `json:"networkIDSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PlacementGroupID *int64 `json:"placementGroupID,omitempty"`
+
+	// PlacementGroupIDRef is a reference to a PlacementGroup used to set
+	// PlacementGroupID
+	// +kubebuilder:validation:Optional
+	PlacementGroupIDRef *xpv1.Reference `json:"placementGroupIDRef,omitempty"`
+
+	// PlacementGroupIDSelector selects a reference to a PlacementGroup used
+	// to set PlacementGroupID
+	// +kubebuilder:validation:Optional
+	PlacementGroupIDSelector *xpv1.Selector `json:"placementGroupIDSelector,omitempty"`
+
+	// PowerOn controls whether the server is running
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	PowerOn bool `json:"powerOn"`
+
+	// PowerSchedule powers the server on and off at set times. It takes the
+	// place of powerOn, which is only used before the schedule first fires.
+	// +kubebuilder:validation:Optional
+	PowerSchedule *apisv1alpha1.PowerSchedule `json:"powerSchedule,omitempty"`
+
+	// PowerOff decides how the server is stopped when it is powered off.
+	// The power is cut straight away when it is not set.
+	// +kubebuilder:validation:Optional
+	PowerOff *apisv1alpha1.PowerOffPolicy `json:"powerOff,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SSHKeys []string `json:"sshKeys,omitempty"`
+
+	// GenerateSSHKey has the provider generate an ed25519 key pair for the
+	// server. The private key is published with the connection details and
+	// the public key is removed from the project when the server is deleted.
+	// +kubebuilder:validation:Optional
+	GenerateSSHKey bool `json:"generateSSHKey,omitempty"`
+
+	// GenerateHostKey has the provider generate the server's ed25519 SSH
+	// host key and install it with cloud-init, so that a known_hosts entry
+	// can be published with the connection details. The private host key is
+	// part of the server's user data, which can be read through the Hetzner
+	// API and from the server's metadata service.
+	// +kubebuilder:validation:Optional
+	GenerateHostKey bool `json:"generateHostKey,omitempty"`
+
+	// ReadinessProbe holds the Ready condition until the server passes it.
+	// The probe is not repeated once it passes. The server is ready once
+	// Hetzner reports it running when not set.
+	// +kubebuilder:validation:Optional
+	ReadinessProbe *apisv1alpha1.ReadinessProbe `json:"readinessProbe,omitempty"`
+
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	StartAfterCreate bool `json:"startAfterCreate"`
+
+	// +kubebuilder:validation:Optional
+	UserData string `json:"userData,omitempty"`
+
+	// UserDataFrom lists Secret and ConfigMap keys whose contents make up
+	// the user data, optionally rendered as Go templates. When there is
+	// more than one part, including userData, they are assembled into a
+	// multipart cloud-init document in the order given, after userData.
+	// +kubebuilder:validation:Optional
+	UserDataFrom []apisv1alpha1.UserDataSource `json:"userDataFrom,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	VolumeIDs []int64 `json:"volumeIDs,omitempty"`
+
+	// VolumeIDRefs are references to Volumes used to set VolumeIDs
+	// +kubebuilder:validation:Optional
+	VolumeIDRefs []xpv1.Reference `json:"volumeIDRefs,omitempty"`
+
+	// VolumeIDSelector selects references to Volumes used to set VolumeIDs
+	// +kubebuilder:validation:Optional
+	VolumeIDSelector *xpv1.Selector `json:"volumeIDSelector,omitempty"`
+}
+
+// ServerObservation are the observable fields of a Server.
+type ServerObservation struct {
+	// +kubebuilder:validation:Optional
+	ID int64 `json:"id,omitempty"`
+
+	// Status of the server, such as running or off
+	// +kubebuilder:validation:Optional
+	Status hcloudsdk.ServerStatus `json:"status,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PublicIPv4 string `json:"publicIPv4,omitempty"`
+
+	// PublicIPv6 is the network assigned to the server
+	// +kubebuilder:validation:Optional
+	PublicIPv6 string `json:"publicIPv6,omitempty"`
+
+	// GeneratedSSHKeyID is the ID of the SSH key generated for the server
+	// +kubebuilder:validation:Optional
+	GeneratedSSHKeyID int64 `json:"generatedSSHKeyID,omitempty"`
+
+	// HostKey is the public SSH host key generated for the server, in
+	// authorized_keys format
+	// +kubebuilder:validation:Optional
+	HostKey string `json:"hostKey,omitempty"`
+
+	// AttachedISO is the name of the ISO attached to the server
+	// +kubebuilder:validation:Optional
+	AttachedISO string `json:"attachedISO,omitempty"`
+
+	// NextPowerTransition is when the power schedule next changes the
+	// server's power state
+	// +kubebuilder:validation:Optional
+	NextPowerTransition *metav1.Time `json:"nextPowerTransition,omitempty"`
+
+	// ShutdownRequestedAt is when a graceful shutdown was requested, while
+	// waiting for the server to stop
+	// +kubebuilder:validation:Optional
+	ShutdownRequestedAt *metav1.Time `json:"shutdownRequestedAt,omitempty"`
+
+	// LastOperation is the outcome of the last operation requested with the
+	// cloud.hetzner.crossplane.io/operation annotation
+	// +kubebuilder:validation:Optional
+	LastOperation *apisv1alpha1.OperationStatus `json:"lastOperation,omitempty"`
+}
+
+// A ServerSpec defines the desired state of a Server.
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.image == oldSelf.forProvider.image",message="forProvider.image cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || (has(self.forProvider.datacenter) ? has(oldSelf.forProvider.datacenter) && self.forProvider.datacenter == oldSelf.forProvider.datacenter : !has(oldSelf.forProvider.datacenter))",message="forProvider.datacenter cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || (has(self.forProvider.location) ? has(oldSelf.forProvider.location) && self.forProvider.location == oldSelf.forProvider.location : !has(oldSelf.forProvider.location))",message="forProvider.location cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.architecture == oldSelf.forProvider.architecture",message="forProvider.architecture cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type ServerSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	// ReplacementPolicy decides what happens when a create-only field of
+	// forProvider is changed. Never rejects the change, Replace deletes and
+	// recreates the Hetzner resource.
+	// +kubebuilder:default:=Never
+	// +kubebuilder:validation:Optional
+	ReplacementPolicy apisv1alpha1.ReplacementPolicy `json:"replacementPolicy,omitempty"`
+
+	ForProvider ServerParameters `json:"forProvider"`
+}
+
+// A ServerStatus represents the observed state of a Server.
+type ServerStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ServerObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Server is a Hetzner Cloud server.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type Server struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServerSpec   `json:"spec"`
+	Status ServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServerList contains a list of Server
+type ServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Server `json:"items"`
+}
+
+// Server type metadata.
+var (
+	ServerKind             = reflect.TypeOf(Server{}).Name()
+	ServerGroupKind        = schema.GroupKind{Group: Group, Kind: ServerKind}.String()
+	ServerKindAPIVersion   = ServerKind + "." + SchemeGroupVersion.String()
+	ServerGroupVersionKind = SchemeGroupVersion.WithKind(ServerKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Server{}, &ServerList{})
+}
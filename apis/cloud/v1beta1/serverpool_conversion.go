@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this ServerPool to the v1alpha1 hub.
+func (src *ServerPool) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.ServerPool)
+	if !ok {
+		return errors.Errorf(errNotHub, ServerPoolKind)
+	}
+	s := src.DeepCopy()
+
+	p := s.Spec.ForProvider
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.ServerPoolSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: v1alpha1.ServerPoolParameters{
+			Replicas:       p.Replicas,
+			NamePattern:    p.NamePattern,
+			Spread:         p.Spread,
+			MaxUnavailable: p.MaxUnavailable,
+			Template: v1alpha1.ServerTemplate{
+				WriteConnectionSecretsToNamespace: p.Template.WriteConnectionSecretsToNamespace,
+				ForProvider:                       v1alpha1.ServerParameters(p.Template.ForProvider),
+			},
+		},
+	}
+	dst.Status = v1alpha1.ServerPoolStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider:     v1alpha1.ServerPoolObservation(s.Status.AtProvider),
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to this ServerPool.
+func (dst *ServerPool) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.ServerPool)
+	if !ok {
+		return errors.Errorf(errNotHub, ServerPoolKind)
+	}
+	s := src.DeepCopy()
+
+	p := s.Spec.ForProvider
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = ServerPoolSpec{
+		ResourceSpec: s.Spec.ResourceSpec,
+		Project:      s.Spec.Project,
+		ForProvider: ServerPoolParameters{
+			Replicas:       p.Replicas,
+			NamePattern:    p.NamePattern,
+			Spread:         p.Spread,
+			MaxUnavailable: p.MaxUnavailable,
+			Template: ServerTemplate{
+				WriteConnectionSecretsToNamespace: p.Template.WriteConnectionSecretsToNamespace,
+				ForProvider:                       ServerParameters(p.Template.ForProvider),
+			},
+		},
+	}
+	dst.Status = ServerPoolStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider:     ServerPoolObservation(s.Status.AtProvider),
+	}
+
+	return nil
+}
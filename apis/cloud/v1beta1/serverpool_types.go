@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ServerPoolParameters are the configurable fields of a ServerPool.
+// +kubebuilder:validation:XValidation:rule="!self.spread || self.replicas <= 10",message="a spread placement group holds at most 10 servers"
+type ServerPoolParameters struct {
+	// Replicas is the number of servers in the pool
+	// +kubebuilder:validation:Minimum:=0
+	Replicas int32 `json:"replicas"`
+
+	// NamePattern names each server. {pool} is replaced with the name of the
+	// pool and {index} with the index of the server, counting from 0.
+	// +kubebuilder:default:="{pool}-{index}"
+	// +kubebuilder:validation:XValidation:rule="self.contains('{index}')",message="namePattern must contain {index}"
+	// +kubebuilder:validation:Optional
+	NamePattern string `json:"namePattern,omitempty"`
+
+	// Spread puts the servers in a spread placement group created for the
+	// pool, so that they run on different physical hosts
+	// +kubebuilder:validation:Optional
+	Spread bool `json:"spread,omitempty"`
+
+	// MaxUnavailable is how many servers may be unavailable while servers
+	// created from an old template are replaced
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	MaxUnavailable int32 `json:"maxUnavailable,omitempty"`
+
+	// Template the servers are created from. Changes to the labels, power
+	// settings, readiness probe, ISO, firewalls, networks, volumes, placement
+	// group and deletion policy are made to the existing servers, while any
+	// other change replaces them one by one.
+	Template ServerTemplate `json:"template"`
+}
+
+// A ServerTemplate describes the servers of a ServerPool.
+type ServerTemplate struct {
+	// WriteConnectionSecretsToNamespace is the namespace each server's
+	// connection secret is written to. The secret is named after the
+	// server.
+	// +kubebuilder:validation:Optional
+	WriteConnectionSecretsToNamespace *string `json:"writeConnectionSecretsToNamespace,omitempty"`
+
+	ForProvider ServerParameters `json:"forProvider"`
+}
+
+// ServerPoolObservation are the observable fields of a ServerPool.
+type ServerPoolObservation struct {
+	// Replicas is the number of servers in the pool
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of servers which are ready
+	// +kubebuilder:validation:Optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpdatedReplicas is the number of servers created from the current
+	// template
+	// +kubebuilder:validation:Optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// AvailableReplicas is the number of servers which are ready and were
+	// created from the current template
+	// +kubebuilder:validation:Optional
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// Servers are the names of the Server objects in the pool
+	// +kubebuilder:validation:Optional
+	Servers []string `json:"servers,omitempty"`
+}
+
+// A ServerPoolSpec defines the desired state of a ServerPool.
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type ServerPoolSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig for
+	// every server in the pool. The default credentials are used when it is
+	// not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	ForProvider ServerPoolParameters `json:"forProvider"`
+}
+
+// A ServerPoolStatus represents the observed state of a ServerPool.
+type ServerPoolStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ServerPoolObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ServerPool is a replicated group of identical Servers.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".spec.forProvider.replicas"
+// +kubebuilder:printcolumn:name="AVAILABLE",type="integer",JSONPath=".status.atProvider.availableReplicas"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.forProvider.replicas,statuspath=.status.atProvider.replicas
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type ServerPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServerPoolSpec   `json:"spec"`
+	Status ServerPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServerPoolList contains a list of ServerPool
+type ServerPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServerPool `json:"items"`
+}
+
+// ServerPool type metadata.
+var (
+	ServerPoolKind             = reflect.TypeOf(ServerPool{}).Name()
+	ServerPoolGroupKind        = schema.GroupKind{Group: Group, Kind: ServerPoolKind}.String()
+	ServerPoolKindAPIVersion   = ServerPoolKind + "." + SchemeGroupVersion.String()
+	ServerPoolGroupVersionKind = SchemeGroupVersion.WithKind(ServerPoolKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ServerPool{}, &ServerPoolList{})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// ConvertTo converts this Volume to the v1alpha1 hub.
+func (src *Volume) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Volume)
+	if !ok {
+		return errors.Errorf(errNotHub, VolumeKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = v1alpha1.VolumeSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		ForProvider: v1alpha1.VolumeParameters{
+			Size:             p.Size,
+			Automount:        p.AutoMount,
+			Format:           p.Format,
+			Labels:           p.Labels,
+			Location:         p.Location,
+			ServerID:         p.ServerID,
+			ServerIDRef:      p.ServerIDRef,
+			ServerIDSelector: p.ServerIDSelector,
+		},
+	}
+	dst.Status = v1alpha1.VolumeStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: v1alpha1.VolumeObservation{
+			ID:          s.Status.AtProvider.ID,
+			Status:      s.Status.AtProvider.Status,
+			LinuxDevice: s.Status.AtProvider.LinuxDevice,
+		},
+	}
+
+	return restoreConversionData(dst, &dst.Status.AtProvider.VolumeParameters)
+}
+
+// ConvertFrom converts the v1alpha1 hub to this Volume.
+func (dst *Volume) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Volume)
+	if !ok {
+		return errors.Errorf(errNotHub, VolumeKind)
+	}
+	s := src.DeepCopy()
+	p := s.Spec.ForProvider
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Spec = VolumeSpec{
+		ResourceSpec:      s.Spec.ResourceSpec,
+		Project:           s.Spec.Project,
+		ReplacementPolicy: s.Spec.ReplacementPolicy,
+		ForProvider: VolumeParameters{
+			Size:             p.Size,
+			AutoMount:        p.Automount,
+			Format:           p.Format,
+			Labels:           p.Labels,
+			Location:         p.Location,
+			ServerID:         p.ServerID,
+			ServerIDRef:      p.ServerIDRef,
+			ServerIDSelector: p.ServerIDSelector,
+		},
+	}
+	dst.Status = VolumeStatus{
+		ResourceStatus: s.Status.ResourceStatus,
+		AtProvider: VolumeObservation{
+			ID:          s.Status.AtProvider.ID,
+			Status:      s.Status.AtProvider.Status,
+			LinuxDevice: s.Status.AtProvider.LinuxDevice,
+		},
+	}
+
+	if params := s.Status.AtProvider.VolumeParameters; params != nil {
+		return saveConversionData(dst, params)
+	}
+	return nil
+}
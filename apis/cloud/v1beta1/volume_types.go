@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// VolumeParameters are the configurable fields of a Volume.
+type VolumeParameters struct {
+	// Size of the volume in GB
+	// +kubebuilder:validation:Minimum:=10
+	// +kubebuilder:validation:Maximum:=10240
+	Size int `json:"size"`
+
+	// +kubebuilder:validation:Optional
+	AutoMount bool `json:"autoMount,omitempty"`
+
+	// +kubebuilder:default:=ext4
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum:=xfs;ext4
+	Format string `json:"format"`
+
+	// +kubebuilder:validation:Optional
+	Labels apisv1alpha1.Labels `json:"labels,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Location *string `json:"location,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ServerID *int64 `json:"serverID,omitempty"`
+
+	// ServerIDRef is a reference to a Server used to set ServerID
+	// +kubebuilder:validation:Optional
+	ServerIDRef *xpv1.Reference `json:"serverIDRef,omitempty"`
+
+	// ServerIDSelector selects a reference to a Server used to set ServerID
+	// +kubebuilder:validation:Optional
+	ServerIDSelector *xpv1.Selector `json:"serverIDSelector,omitempty"`
+}
+
+// VolumeObservation are the observable fields of a Volume.
+type VolumeObservation struct {
+	// +kubebuilder:validation:Optional
+	ID int64 `json:"id,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Status hcloudsdk.VolumeStatus `json:"status,omitempty"`
+
+	// LinuxDevice is the path of the volume on the server it is attached to
+	// +kubebuilder:validation:Optional
+	LinuxDevice string `json:"linuxDevice,omitempty"`
+}
+
+// A VolumeSpec defines the desired state of a Volume.
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || self.forProvider.format == oldSelf.forProvider.format",message="forProvider.format cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="self.replacementPolicy == 'Replace' || (has(self.forProvider.location) ? has(oldSelf.forProvider.location) && self.forProvider.location == oldSelf.forProvider.location : !has(oldSelf.forProvider.location))",message="forProvider.location cannot be changed unless replacementPolicy is Replace"
+// +kubebuilder:validation:XValidation:rule="has(self.project) == has(oldSelf.project) && (!has(self.project) || self.project == oldSelf.project)",message="project is immutable"
+type VolumeSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+
+	// Project selects named project credentials from the ProviderConfig. The
+	// default credentials are used when it is not set.
+	// +kubebuilder:validation:Optional
+	Project *string `json:"project,omitempty"`
+
+	// ReplacementPolicy decides what happens when a create-only field of
+	// forProvider is changed. Never rejects the change, Replace deletes and
+	// recreates the Hetzner resource.
+	// +kubebuilder:default:=Never
+	// +kubebuilder:validation:Optional
+	ReplacementPolicy apisv1alpha1.ReplacementPolicy `json:"replacementPolicy,omitempty"`
+
+	ForProvider VolumeParameters `json:"forProvider"`
+}
+
+// A VolumeStatus represents the observed state of a Volume.
+type VolumeStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          VolumeObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Volume is a Hetzner Cloud volume.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,hetzner}
+type Volume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeSpec   `json:"spec"`
+	Status VolumeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeList contains a list of Volume
+type VolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Volume `json:"items"`
+}
+
+// Volume type metadata.
+var (
+	VolumeKind             = reflect.TypeOf(Volume{}).Name()
+	VolumeGroupKind        = schema.GroupKind{Group: Group, Kind: VolumeKind}.String()
+	VolumeKindAPIVersion   = VolumeKind + "." + SchemeGroupVersion.String()
+	VolumeGroupVersionKind = SchemeGroupVersion.WithKind(VolumeKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Volume{}, &VolumeList{})
+}
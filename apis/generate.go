@@ -26,13 +26,21 @@ limitations under the License.
 // Generate deepcopy methodsets and CRD manifests
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen object:headerFile=../hack/boilerplate.go.txt paths=./... crd:crdVersions=v1 output:artifacts:config=../package/crds
 
+// Add conversion webhooks to CRDs with more than one version
+//go:generate bash ../hack/crd-conversion.sh ../package/crds
+
+// Generate validating webhook configurations
+//go:generate rm -rf ../package/webhookconfigurations
+//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen webhook paths=../internal/webhook/... output:webhook:artifacts:config=../package/webhookconfigurations
+
 // Generate crossplane-runtime methodsets (resource.Claim, etc)
 //go:generate go run -tags generate github.com/crossplane/crossplane-tools/cmd/angryjet generate-methodsets --header-file=../hack/boilerplate.go.txt ./...
 
 package apis
 
 import (
-	_ "sigs.k8s.io/controller-tools/cmd/controller-gen" //nolint:typecheck
+	_ "sigs.k8s.io/controller-runtime/tools/setup-envtest" //nolint:typecheck
+	_ "sigs.k8s.io/controller-tools/cmd/controller-gen"    //nolint:typecheck
 
 	_ "github.com/crossplane/crossplane-tools/cmd/angryjet" //nolint:typecheck
 )
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	cloudv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	cloudv1beta1 "github.com/mrsimonemms/provider-hetzner/apis/cloud/v1beta1"
 	hetznerv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
@@ -29,6 +30,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		hetznerv1alpha1.SchemeBuilder.AddToScheme,
 		cloudv1alpha1.SchemeBuilder.AddToScheme,
+		cloudv1beta1.SchemeBuilder.AddToScheme,
 	)
 }
 
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels which trace a Hetzner resource back to the Kubernetes object
+// managing it.
+const (
+	CompositeLabel    = "crossplane.io/composite"
+	ResourceNameLabel = "crossplane.io/name"
+	ResourceUIDLabel  = "crossplane.io/uid"
+)
+
+// LabelMode controls how labels that break the Hetzner label grammar are
+// handled
+type LabelMode string
+
+const (
+	// LabelModeReject fails the operation when a label is invalid
+	LabelModeReject LabelMode = "Reject"
+
+	// LabelModeSanitise rewrites invalid labels so they are accepted,
+	// dropping any which cannot be rewritten
+	LabelModeSanitise LabelMode = "Sanitise"
+)
+
+// Labels are applied to the Hetzner resource. Keys and values must follow the
+// Hetzner label rules at https://docs.hetzner.cloud/#labels
+// +kubebuilder:validation:MaxProperties:=64
+// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^([a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[a-zA-Z0-9]([-a-zA-Z0-9_.]{0,61}[a-zA-Z0-9])?$'))",message="label keys must be an optional DNS subdomain prefix and a name of up to 63 alphanumeric characters, '-', '_' or '.' that starts and ends with an alphanumeric character"
+// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('hetzner.cloud/'))",message="the hetzner.cloud/ label prefix is reserved"
+type Labels map[string]LabelValue
+
+// LabelValue is empty or up to 63 alphanumeric characters, '-', '_' or '.'
+// that starts and ends with an alphanumeric character.
+// +kubebuilder:validation:MaxLength:=63
+// +kubebuilder:validation:Pattern:=`^([a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?)?$`
+type LabelValue string
+
+// Map returns the labels in the form used by the Hetzner API
+func (l Labels) Map() map[string]string {
+	if l == nil {
+		return nil
+	}
+
+	labels := make(map[string]string, len(l))
+	for k, v := range l {
+		labels[k] = string(v)
+	}
+
+	return labels
+}
+
+// maxLabelValueLength is the longest label value Hetzner accepts
+const maxLabelValueLength = 63
+
+// labelHashLength is how many hex characters of a hash are kept when a
+// value is shortened to fit in a label
+const labelHashLength = 8
+
+// ResourceLabels returns the labels that trace a Hetzner resource back to the
+// Kubernetes object managing it. Object names can be longer than a label
+// value, so long names are shortened with a hash that keeps them distinct.
+func ResourceLabels(obj metav1.Object) map[string]string {
+	labels := map[string]string{
+		ResourceNameLabel: shortenLabelValue(obj.GetName()),
+		ResourceUIDLabel:  string(obj.GetUID()),
+	}
+
+	if composite, ok := obj.GetLabels()[CompositeLabel]; ok {
+		labels[CompositeLabel] = composite
+	}
+
+	return labels
+}
+
+// shortenLabelValue fits a value made of characters valid in a label into a
+// label value by replacing its end with a hash of the whole value
+func shortenLabelValue(value string) string {
+	if len(value) <= maxLabelValueLength {
+		return value
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:])[:labelHashLength]
+
+	return value[:maxLabelValueLength-labelHashLength-1] + "-" + hash
+}
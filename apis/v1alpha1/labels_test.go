@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func object(name string, labels map[string]string) *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{Name: name, UID: "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f", Labels: labels}
+}
+
+func TestResourceLabels(t *testing.T) {
+	long := strings.Repeat("a", 60) + "." + strings.Repeat("b", 60)
+
+	cases := map[string]struct {
+		reason string
+		obj    metav1.Object
+		want   map[string]string
+	}{
+		"NameAndUID": {
+			reason: "The name and UID should be labelled",
+			obj:    object("example", nil),
+			want: map[string]string{
+				ResourceNameLabel: "example",
+				ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			},
+		},
+		"Composite": {
+			reason: "The composite owner should be labelled when the object has one",
+			obj:    object("example", map[string]string{CompositeLabel: "example-xyz", "team": "platform"}),
+			want: map[string]string{
+				ResourceNameLabel: "example",
+				ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+				CompositeLabel:    "example-xyz",
+			},
+		},
+		"LongName": {
+			reason: "A name longer than a label value should be shortened with a hash of the full name",
+			obj:    object(long, nil),
+			want: map[string]string{
+				ResourceNameLabel: strings.Repeat("a", 54) + "-0f7ea3f7",
+				ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ResourceLabels(tc.obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nResourceLabels(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestShortenLabelValue(t *testing.T) {
+	a := shortenLabelValue(strings.Repeat("a", 64))
+	b := shortenLabelValue(strings.Repeat("a", 65))
+
+	if len(a) != maxLabelValueLength || len(b) != maxLabelValueLength {
+		t.Errorf("shortenLabelValue(...): want %d characters, got %d and %d", maxLabelValueLength, len(a), len(b))
+	}
+	if a == b {
+		t.Errorf("shortenLabelValue(...): names sharing a prefix should stay distinct, both got %q", a)
+	}
+}
+
+func TestDefaultLabels(t *testing.T) {
+	obj := object("example", nil)
+
+	cases := map[string]struct {
+		reason string
+		policy *LabelPolicy
+		want   map[string]string
+	}{
+		"NoPolicy": {
+			reason: "A ProviderConfig without a label policy adds no labels",
+			want:   map[string]string{},
+		},
+		"Default": {
+			reason: "The default labels should be added",
+			policy: &LabelPolicy{Default: Labels{"team": "platform"}},
+			want:   map[string]string{"team": "platform"},
+		},
+		"PropagateMetadata": {
+			reason: "The object's metadata should be added alongside the default labels",
+			policy: &LabelPolicy{Default: Labels{"team": "platform"}, PropagateMetadata: true},
+			want: map[string]string{
+				"team":            "platform",
+				ResourceNameLabel: "example",
+				ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			},
+		},
+		"MetadataOverridesDefault": {
+			reason: "Metadata labels should take precedence over default labels with the same key",
+			policy: &LabelPolicy{Default: Labels{ResourceNameLabel: "other"}, PropagateMetadata: true},
+			want: map[string]string{
+				ResourceNameLabel: "example",
+				ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.policy.DefaultLabels(obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDefaultLabels(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// AnnotationKeyOperation requests a one-off operation on a server, such as
+// a reboot. The provider records the outcome in the server's status and then
+// removes it. The operation may be run again if the outcome can't be saved,
+// but not because the annotation couldn't be removed.
+const AnnotationKeyOperation = "cloud.hetzner.crossplane.io/operation"
+
+// A ServerOperation is a one-off operation requested on a server.
+type ServerOperation string
+
+const (
+	// ServerOperationReboot asks the operating system to reboot
+	ServerOperationReboot ServerOperation = "Reboot"
+
+	// ServerOperationReset cuts the power and starts the server again
+	ServerOperationReset ServerOperation = "Reset"
+
+	// ServerOperationResetPassword sets a new root password, which is
+	// published with the connection details. It needs the qemu guest agent
+	// running on the server.
+	ServerOperationResetPassword ServerOperation = "ResetPassword"
+)
+
+// ServerOperations are the operations that can be requested.
+var ServerOperations = []ServerOperation{
+	ServerOperationReboot,
+	ServerOperationReset,
+	ServerOperationResetPassword,
+}
+
+// OperationResult is the outcome of an operation.
+type OperationResult string
+
+const (
+	// OperationSucceeded is an operation which Hetzner completed
+	OperationSucceeded OperationResult = "Succeeded"
+
+	// OperationFailed is an operation which was rejected or did not
+	// complete
+	OperationFailed OperationResult = "Failed"
+)
+
+// An OperationStatus records the last operation requested on a resource.
+type OperationStatus struct {
+	// Operation which was requested
+	Operation ServerOperation `json:"operation"`
+
+	// Result of the operation
+	Result OperationResult `json:"result"`
+
+	// Message explaining why the operation failed
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Time the operation finished
+	Time metav1.Time `json:"time"`
+
+	// AnnotationRemoved is set once the annotation requesting the operation
+	// has been removed. Until then the annotation is not taken as a new
+	// request, so the operation is not run twice.
+	// +kubebuilder:validation:Optional
+	AnnotationRemoved bool `json:"annotationRemoved,omitempty"`
+}
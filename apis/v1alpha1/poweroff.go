@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// PowerOffMethod is how a server is stopped when it is powered off.
+// +kubebuilder:validation:Enum:=PowerOff;Shutdown
+type PowerOffMethod string
+
+const (
+	// PowerOffMethodPowerOff cuts the power straight away, like pulling the
+	// plug. Anything not yet written to disk is lost.
+	PowerOffMethodPowerOff PowerOffMethod = "PowerOff"
+
+	// PowerOffMethodShutdown sends an ACPI shutdown request so the operating
+	// system can stop cleanly, cutting the power if it has not stopped
+	// within the timeout
+	PowerOffMethodShutdown PowerOffMethod = "Shutdown"
+)
+
+// A PowerOffPolicy decides how a server is stopped when it is powered off.
+type PowerOffPolicy struct {
+	// Method used to stop the server
+	// +kubebuilder:default:=PowerOff
+	// +kubebuilder:validation:Optional
+	Method PowerOffMethod `json:"method,omitempty"`
+
+	// ShutdownTimeoutSeconds to wait for a Shutdown before cutting the power
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	ShutdownTimeoutSeconds int `json:"shutdownTimeoutSeconds,omitempty"`
+}
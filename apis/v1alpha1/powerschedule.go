@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+// powerScheduleLookback are the windows searched, in turn, for the last time
+// a schedule fired. Frequent schedules are found in the first window and
+// infrequent ones without stepping through every minute of a year.
+var powerScheduleLookback = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	31 * 24 * time.Hour,
+	366 * 24 * time.Hour,
+}
+
+// A PowerSchedule powers a server on and off at set times, such as keeping
+// it off outside working hours. The server is in whichever state was
+// scheduled most recently, so it is put right at the next poll when the
+// provider was not running at the time.
+type PowerSchedule struct {
+	// On is a cron expression for when the server is powered on, such as
+	// "0 8 * * 1-5"
+	On string `json:"on"`
+
+	// Off is a cron expression for when the server is powered off, such as
+	// "0 20 * * 1-5"
+	Off string `json:"off"`
+
+	// TimeZone the expressions are evaluated in, as an IANA name such as
+	// Europe/Berlin
+	// +kubebuilder:default:=UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Validate returns an error if an expression or the time zone is invalid.
+func (p *PowerSchedule) Validate() error {
+	_, _, _, err := p.parse()
+	return err
+}
+
+// State returns whether the server is scheduled to be on at the given time
+// and when that next changes. When neither expression has fired within the
+// last year, the server is reported in the fallback state.
+func (p *PowerSchedule) State(now time.Time, fallback bool) (bool, time.Time, error) {
+	on, off, loc, err := p.parse()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	now = now.In(loc)
+	lastOn, lastOff := lastFired(on, now), lastFired(off, now)
+
+	powerOn := fallback
+	switch {
+	case lastOn.IsZero() && lastOff.IsZero():
+	case lastOn.After(lastOff):
+		// Off wins when both fire at the same time
+		powerOn = true
+	default:
+		powerOn = false
+	}
+
+	next := on.Next(now)
+	if powerOn {
+		next = off.Next(now)
+	}
+
+	return powerOn, next, nil
+}
+
+func (p *PowerSchedule) parse() (cron.Schedule, cron.Schedule, *time.Location, error) {
+	on, err := cron.ParseStandard(p.On)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid on schedule")
+	}
+	off, err := cron.ParseStandard(p.Off)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid off schedule")
+	}
+
+	zone := p.TimeZone
+	if zone == "" {
+		zone = "UTC"
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid time zone %q", zone)
+	}
+
+	return on, off, loc, nil
+}
+
+// lastFired returns the last time the schedule fired at or before now, or
+// the zero time if it did not fire within the longest lookback window.
+func lastFired(schedule cron.Schedule, now time.Time) time.Time {
+	for _, window := range powerScheduleLookback {
+		// Next is strictly after the time given, so step back a second to
+		// include a fire at the start of the window
+		var last time.Time
+		for t := schedule.Next(now.Add(-window - time.Second)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+			last = t
+		}
+		if !last.IsZero() {
+			return last
+		}
+	}
+
+	return time.Time{}
+}
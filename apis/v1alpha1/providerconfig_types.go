@@ -23,12 +23,81 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
 )
 
 // A ProviderConfigSpec defines the desired state of a ProviderConfig.
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Projects are credentials for further Hetzner projects. API tokens are
+	// scoped to a single project, so managed resources select one of these by
+	// name with spec.project. Resources which do not set a project use the
+	// default credentials.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Projects []ProjectCredentials `json:"projects,omitempty"`
+
+	// Labels configures the labels applied to every Hetzner resource.
+	// +kubebuilder:validation:Optional
+	Labels *LabelPolicy `json:"labels,omitempty"`
+}
+
+// LabelPolicy controls the labels added to every Hetzner resource managed
+// through a ProviderConfig.
+type LabelPolicy struct {
+	// Default labels are merged into every resource's labels. Labels set on
+	// the managed resource take precedence.
+	// +kubebuilder:validation:Optional
+	Default Labels `json:"default,omitempty"`
+
+	// PropagateMetadata adds the managed resource's Kubernetes name, UID and
+	// composite owner as labels for traceability. Names longer than 63
+	// characters are shortened and end with a hash of the full name.
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	PropagateMetadata bool `json:"propagateMetadata"`
+
+	// Mode controls how labels which are invalid in Hetzner are handled.
+	// Reject fails the operation with an error describing every invalid
+	// label. Sanitise rewrites them, dropping any that cannot be rewritten
+	// and failing if two keys would be rewritten to the same key.
+	// +kubebuilder:default:=Reject
+	// +kubebuilder:validation:Enum:=Reject;Sanitise
+	// +kubebuilder:validation:Optional
+	Mode LabelMode `json:"mode,omitempty"`
+}
+
+// DefaultLabels returns the labels the policy adds to the Hetzner resource
+// managed by obj.
+func (l *LabelPolicy) DefaultLabels(obj metav1.Object) map[string]string {
+	labels := map[string]string{}
+	if l == nil {
+		return labels
+	}
+
+	for k, v := range l.Default.Map() {
+		labels[k] = v
+	}
+
+	if l.PropagateMetadata {
+		for k, v := range ResourceLabels(obj) {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
+
+// GetMode returns how invalid labels should be handled
+func (l *LabelPolicy) GetMode() LabelMode {
+	if l == nil || l.Mode == "" {
+		return LabelModeReject
+	}
+
+	return l.Mode
 }
 
 // ProviderCredentials required to authenticate.
@@ -40,6 +109,16 @@ type ProviderCredentials struct {
 	xpv1.CommonCredentialSelectors `json:",inline"`
 }
 
+// ProjectCredentials are the credentials for a named Hetzner project.
+type ProjectCredentials struct {
+	// Name used by managed resources to select the project.
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Credentials required to authenticate to the project.
+	Credentials ProviderCredentials `json:"credentials"`
+}
+
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
@@ -60,6 +139,22 @@ type ProviderConfig struct {
 	Status ProviderConfigStatus `json:"status,omitempty"`
 }
 
+// GetCredentials returns the credentials for the named project, or the
+// default credentials if no project is given.
+func (pc *ProviderConfig) GetCredentials(project *string) (ProviderCredentials, error) {
+	if project == nil || *project == "" {
+		return pc.Spec.Credentials, nil
+	}
+
+	for _, p := range pc.Spec.Projects {
+		if p.Name == *project {
+			return p.Credentials, nil
+		}
+	}
+
+	return ProviderCredentials{}, errors.Errorf("project %q not found in ProviderConfig %s", *project, pc.GetName())
+}
+
 // +kubebuilder:object:root=true
 
 // ProviderConfigList contains a list of ProviderConfig.
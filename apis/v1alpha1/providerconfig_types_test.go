@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func secretCredentials(name string) ProviderCredentials {
+	return ProviderCredentials{
+		Source: xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+			SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: name, Namespace: "default"},
+				Key:             "token",
+			},
+		},
+	}
+}
+
+func TestGetCredentials(t *testing.T) {
+	pc := &ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: ProviderConfigSpec{
+			Credentials: secretCredentials("default"),
+			Projects: []ProjectCredentials{
+				{Name: "staging", Credentials: secretCredentials("staging")},
+				{Name: "production", Credentials: secretCredentials("production")},
+			},
+		},
+	}
+
+	project := func(name string) *string { return &name }
+
+	type want struct {
+		creds ProviderCredentials
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		project *string
+		want    want
+	}{
+		"NoProject": {
+			reason: "Resources without a project should use the default credentials",
+			want:   want{creds: secretCredentials("default")},
+		},
+		"EmptyProject": {
+			reason:  "An empty project should be treated as no project",
+			project: project(""),
+			want:    want{creds: secretCredentials("default")},
+		},
+		"Project": {
+			reason:  "A named project should use that project's credentials",
+			project: project("production"),
+			want:    want{creds: secretCredentials("production")},
+		},
+		"MissingProject": {
+			reason:  "A project the ProviderConfig doesn't have should be an error rather than a fall back to the default",
+			project: project("development"),
+			want:    want{err: errors.New(`project "development" not found in ProviderConfig example`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := pc.GetCredentials(tc.project)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetCredentials(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("\n%s\nGetCredentials(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
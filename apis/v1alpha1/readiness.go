@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ReadinessProbeType is the check a ReadinessProbe makes.
+// +kubebuilder:validation:Enum:=TCP;SSH;Label
+type ReadinessProbeType string
+
+const (
+	// ReadinessProbeTCP passes once a TCP connection to the port succeeds
+	ReadinessProbeTCP ReadinessProbeType = "TCP"
+
+	// ReadinessProbeSSH passes once the port answers with an SSH banner
+	ReadinessProbeSSH ReadinessProbeType = "SSH"
+
+	// ReadinessProbeLabel passes once the server has the label, which is
+	// typically set through the Hetzner API by cloud-init when it finishes
+	ReadinessProbeLabel ReadinessProbeType = "Label"
+)
+
+// A ReadinessProbe holds a server's Ready condition until the server can be
+// used, rather than as soon as Hetzner reports it running. The probe is not
+// repeated once it passes, so the server stays ready if it later stops
+// answering.
+// +kubebuilder:validation:XValidation:rule="self.type != 'Label' || has(self.label)",message="label is required for Label probes"
+type ReadinessProbe struct {
+	// Type of check to make
+	Type ReadinessProbeType `json:"type"`
+
+	// Port checked by TCP and SSH probes
+	// +kubebuilder:default:=22
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +kubebuilder:validation:Optional
+	Port int `json:"port,omitempty"`
+
+	// Label the server must have for Label probes. The provider leaves it
+	// in place rather than treating it as drift.
+	// +kubebuilder:validation:Optional
+	Label *ReadinessLabel `json:"label,omitempty"`
+
+	// TimeoutSeconds after the server is created before the probe is
+	// reported as failed
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// A ReadinessLabel is a label checked by a ReadinessProbe.
+type ReadinessLabel struct {
+	// Key of the label
+	Key string `json:"key"`
+
+	// Value the label must have. Any value passes when it is not set.
+	// +kubebuilder:validation:Optional
+	Value *string `json:"value,omitempty"`
+}
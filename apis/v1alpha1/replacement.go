@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ReplacementPolicy decides what happens when a field that Hetzner cannot
+// change after creation is edited.
+// +kubebuilder:validation:Enum:=Never;Replace
+type ReplacementPolicy string
+
+const (
+	// ReplacementPolicyNever rejects changes to create-only fields
+	ReplacementPolicyNever ReplacementPolicy = "Never"
+
+	// ReplacementPolicyReplace deletes and recreates the Hetzner resource
+	// when a create-only field changes. The resource reports a
+	// ReplacementPending condition until it is next updated, which does not
+	// happen under an Observe-only management policy. Anything stored only on
+	// the old resource, such as the contents of a disk, is lost.
+	ReplacementPolicyReplace ReplacementPolicy = "Replace"
+)
+
+// TypeReplacementPending is set while the Hetzner resource is due to be
+// deleted and recreated because a create-only field changed
+const TypeReplacementPending xpv1.ConditionType = "ReplacementPending"
+
+// Reasons a resource is or is not pending replacement.
+const (
+	ReasonCreateOnlyFieldChanged    xpv1.ConditionReason = "CreateOnlyFieldChanged"
+	ReasonCreateOnlyFieldsUnchanged xpv1.ConditionReason = "CreateOnlyFieldsUnchanged"
+)
+
+// ReplacementPending returns a condition that indicates the resource will be
+// replaced on its next update.
+func ReplacementPending() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReplacementPending,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCreateOnlyFieldChanged,
+		Message:            "a create-only field changed, so the resource will be deleted and recreated",
+	}
+}
+
+// ReplacementNotPending returns a condition that indicates the resource's
+// create-only fields match it, either because it was replaced or because the
+// change was reverted.
+func ReplacementNotPending() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReplacementPending,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCreateOnlyFieldsUnchanged,
+	}
+}
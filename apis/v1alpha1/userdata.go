@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A UserDataSource is a Secret or ConfigMap key holding part of a server's
+// user data.
+// +kubebuilder:validation:XValidation:rule="has(self.secretKeyRef) != has(self.configMapKeyRef)",message="exactly one of secretKeyRef or configMapKeyRef is required"
+type UserDataSource struct {
+	// SecretKeyRef selects a key of a Secret
+	// +kubebuilder:validation:Optional
+	SecretKeyRef *xpv1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// ConfigMapKeyRef selects a key of a ConfigMap
+	// +kubebuilder:validation:Optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// ContentType of the part when the user data is assembled into a
+	// multipart cloud-init document. It is detected from the first line of
+	// the content when not set, such as text/cloud-config for #cloud-config.
+	// +kubebuilder:validation:Optional
+	ContentType string `json:"contentType,omitempty"`
+
+	// Template renders the content as a Go template before it is used. The
+	// server's name, labels, image, server type, location, datacenter and
+	// the IDs of its firewalls, networks, placement group and volumes are
+	// available as .Name, .Labels, .Image, .ServerType, .Location,
+	// .Datacenter, .FirewallIDs, .NetworkIDs, .PlacementGroupID and
+	// .VolumeIDs.
+	// +kubebuilder:validation:Optional
+	Template bool `json:"template,omitempty"`
+}
+
+// A ConfigMapKeySelector is a reference to a ConfigMap key in an arbitrary
+// namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap
+	Namespace string `json:"namespace"`
+
+	// Key whose value is selected
+	Key string `json:"key"`
+}
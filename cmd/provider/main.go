@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+	_ "time/tzdata" // Power schedules name time zones the image may not have
 
 	"gopkg.in/alecthomas/kingpin.v2"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -29,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -41,6 +43,8 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	hetzner "github.com/mrsimonemms/provider-hetzner/internal/controller"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
+	validation "github.com/mrsimonemms/provider-hetzner/internal/webhook"
 )
 
 func main() {
@@ -56,6 +60,13 @@ func main() {
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+
+		otlpEndpoint     = app.Flag("otlp-endpoint", "OTLP gRPC endpoint to export traces to, such as otel-collector:4317. Tracing is disabled if unset.").Envar("OTLP_ENDPOINT").String()
+		otlpInsecure     = app.Flag("otlp-insecure", "Connect to the OTLP endpoint without TLS.").Default("false").Envar("OTLP_INSECURE").Bool()
+		traceSampleRatio = app.Flag("trace-sample-ratio", "Fraction of reconciles to trace, between 0 and 1.").Default("1").Envar("TRACE_SAMPLE_RATIO").Float64()
+
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "Directory containing tls.crt and tls.key for the webhook server, which serves validation and conversion between API versions.").Default("/webhook/tls").Envar("WEBHOOK_TLS_CERT_DIR").String()
+		webhookPort       = app.Flag("webhook-port", "Port the webhook server listens on.").Default("9443").Envar("WEBHOOK_PORT").Int()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -68,6 +79,23 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    *otlpEndpoint,
+		Insecure:    *otlpInsecure,
+		SampleRatio: *traceSampleRatio,
+	})
+	kingpin.FatalIfError(err, "Cannot set up tracing")
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Info("Cannot flush traces", "error", err)
+		}
+	}()
+	if *otlpEndpoint != "" {
+		log.Info("Tracing enabled", "endpoint", *otlpEndpoint, "sample-ratio", *traceSampleRatio)
+	}
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -90,6 +118,11 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookTLSCertDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Hetzner APIs to scheme")
@@ -127,5 +160,10 @@ func main() {
 	}
 
 	kingpin.FatalIfError(hetzner.Setup(mgr, o), "Cannot setup Hetzner controllers")
+
+	// The webhook server always runs as the CRDs convert between API versions
+	// through it
+	kingpin.FatalIfError(validation.Setup(mgr), "Cannot setup Hetzner webhooks")
+
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
@@ -0,0 +1,44 @@
+#!/usr/bin/env bash
+
+# Copyright 2024 The Crossplane Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Adds a conversion webhook to every CRD in the given directory that serves
+# more than one version. controller-gen has no marker for this. Crossplane
+# replaces the placeholder service and adds the CA bundle when the provider
+# is installed.
+set -euo pipefail
+
+for crd in "${1:?usage: $0 <crd directory>}"/*.yaml; do
+  if [ "$(grep -c '^    name: v[0-9]' "${crd}")" -lt 2 ]; then
+    continue
+  fi
+
+  awk '
+    { print }
+    /^spec:$/ {
+      print "  conversion:"
+      print "    strategy: Webhook"
+      print "    webhook:"
+      print "      clientConfig:"
+      print "        service:"
+      print "          name: webhook-service"
+      print "          namespace: system"
+      print "          path: /convert"
+      print "      conversionReviewVersions:"
+      print "      - v1"
+    }
+  ' "${crd}" > "${crd}.tmp"
+  mv "${crd}.tmp" "${crd}"
+done
@@ -38,6 +38,7 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -51,7 +52,8 @@ const (
 )
 
 // Setup adds a controller that reconciles Firewall managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// The options are given to every Hetzner client it creates.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	name := managed.ControllerName(v1alpha1.FirewallGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -65,6 +67,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newServiceFn: hcloud.NewClient,
+			clientOpts:   opts,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
@@ -84,7 +87,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds string) (*hcloud.Client, error)
+	newServiceFn func(creds string, opts ...hcloud.Option) (*hcloud.Client, error)
+	clientOpts   []hcloud.Option
 }
 
 // Connect typically produces an ExternalClient by:
@@ -107,21 +111,31 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
+	cd, err := pc.GetCredentials(cr.Spec.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(string(data))
+	svc, err := c.newServiceFn(string(data), append([]hcloud.Option{
+		hcloud.WithDefaultLabels(pc.Spec.Labels.DefaultLabels(mg)),
+		hcloud.WithLabelMode(pc.Spec.Labels.GetMode()),
+		hcloud.WithProviderConfig(pc.Name, cr.Spec.Project),
+	}, c.clientOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{
+	return tracing.NewExternalClient(v1alpha1.FirewallKind, func(mg resource.Managed) int64 {
+		return mg.(*v1alpha1.Firewall).Status.AtProvider.ID
+	}, &external{
 		kube:   c.kube,
 		hcloud: svc,
-	}, nil
+	}), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -137,7 +151,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotFirewall)
 	}
 
-	firewall, _, err := c.hcloud.Client.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
+	firewall, _, err := c.hcloud.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
@@ -147,9 +161,24 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	cr.SetConditions(xpv1.Available())
 
+	want, err := getFirewallResources(cr.Spec.ForProvider.ApplyTo)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to convert firewall resources")
+	}
+	applied, err := appliedFirewallResources(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// Resources removed from the live firewall outside of the provider are
+	// drift too
+	add, remove := diffFirewallResources(want, applied, firewall.AppliedTo)
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: cr.IsUpToDate(),
+		ResourceExists: true,
+		ResourceUpToDate: cr.IsUpToDate() &&
+			len(add) == 0 && len(remove) == 0 &&
+			c.hcloud.LabelsUpToDate(firewall.Labels, cr.Spec.ForProvider.Labels.Map()),
 	}, nil
 }
 
@@ -166,15 +195,20 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to convert firewall rules")
 	}
 
-	applyTo := make([]hcloudsdk.FirewallResource, 0)
-	for _, a := range cr.Spec.ForProvider.ApplyTo {
-		applyTo = append(applyTo, a.ToFirewallResource())
+	applyTo, err := getFirewallResources(cr.Spec.ForProvider.ApplyTo)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "failed to convert firewall resources")
+	}
+
+	labels, err := c.hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels.Map())
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
-	firewall, _, err := c.hcloud.Client.Firewall.Create(ctx, hcloudsdk.FirewallCreateOpts{
+	firewall, _, err := c.hcloud.Firewall.Create(ctx, hcloudsdk.FirewallCreateOpts{
 		Name:    cr.ObjectMeta.Name,
 		ApplyTo: applyTo,
-		Labels:  hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels),
+		Labels:  labels,
 		Rules:   rules,
 	})
 	if err != nil {
@@ -203,7 +237,17 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to convert firewall rules")
 	}
 
-	firewall, _, err := c.hcloud.Client.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
+	applyTo, err := getFirewallResources(target.ApplyTo)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to convert firewall resources")
+	}
+
+	applied, err := appliedFirewallResources(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	firewall, _, err := c.hcloud.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to find firewall")
 	}
@@ -211,22 +255,33 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, fmt.Errorf("firewall not found")
 	}
 
-	if _, _, err := c.hcloud.Client.Firewall.Update(ctx, firewall, hcloudsdk.FirewallUpdateOpts{
-		Labels: hcloud.ApplyDefaultLabels(target.Labels),
+	labels, err := c.hcloud.UpdateLabels(firewall.Labels, target.Labels.Map())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if _, _, err := c.hcloud.Firewall.Update(ctx, firewall, hcloudsdk.FirewallUpdateOpts{
+		Labels: labels,
 	}); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update firewall")
 	}
 
-	if err := c.removeResources(ctx, firewall, firewall.AppliedTo); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to remove resources")
+	// Set the rules before changing what the firewall is applied to, so new
+	// resources are never protected by outdated rules
+	if err := c.setRules(ctx, firewall, rules); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to set rules")
 	}
 
-	if err := c.applyResources(ctx, firewall, target.ApplyTo); err != nil {
+	// Apply new resources before removing old ones, leaving the resources
+	// wanted both before and after untouched
+	add, remove := diffFirewallResources(applyTo, applied, firewall.AppliedTo)
+
+	if err := c.applyResources(ctx, firewall, add); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to apply resources")
 	}
 
-	if err := c.setRules(ctx, firewall, rules); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to set rules")
+	if err := c.removeResources(ctx, firewall, remove); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to remove resources")
 	}
 
 	cr.Status.AtProvider.FirewallParameters = target.DeepCopy()
@@ -245,7 +300,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(xpv1.Deleting())
 
-	firewall, _, err := c.hcloud.Client.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
+	firewall, _, err := c.hcloud.Firewall.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return errors.Wrap(err, "failed to get firewall to delete")
 	}
@@ -257,20 +312,19 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.Wrap(err, "failed to remove resources")
 	}
 
-	if _, err := c.hcloud.Client.Firewall.Delete(ctx, firewall); err != nil {
+	if _, err := c.hcloud.Firewall.Delete(ctx, firewall); err != nil {
 		return errors.Wrap(err, "failed to delete firewall")
 	}
 
 	return nil
 }
 
-func (c *external) applyResources(ctx context.Context, firewall *hcloudsdk.Firewall, resources []v1alpha1.FirewallApplyTo) error {
-	applyTo := make([]hcloudsdk.FirewallResource, 0)
-	for _, a := range resources {
-		applyTo = append(applyTo, a.ToFirewallResource())
+func (c *external) applyResources(ctx context.Context, firewall *hcloudsdk.Firewall, resources []hcloudsdk.FirewallResource) error {
+	if len(resources) == 0 {
+		return nil
 	}
 
-	applyActions, _, err := c.hcloud.Client.Firewall.ApplyResources(ctx, firewall, applyTo)
+	applyActions, _, err := c.hcloud.Firewall.ApplyResources(ctx, firewall, resources)
 	if err != nil {
 		return errors.Wrap(err, "failed to apply resources")
 	}
@@ -285,7 +339,11 @@ func (c *external) applyResources(ctx context.Context, firewall *hcloudsdk.Firew
 }
 
 func (c *external) removeResources(ctx context.Context, firewall *hcloudsdk.Firewall, resources []hcloudsdk.FirewallResource) error {
-	removeActions, _, err := c.hcloud.Client.Firewall.RemoveResources(ctx, firewall, resources)
+	if len(resources) == 0 {
+		return nil
+	}
+
+	removeActions, _, err := c.hcloud.Firewall.RemoveResources(ctx, firewall, resources)
 	if err != nil {
 		return err
 	}
@@ -300,7 +358,7 @@ func (c *external) removeResources(ctx context.Context, firewall *hcloudsdk.Fire
 }
 
 func (c *external) setRules(ctx context.Context, firewall *hcloudsdk.Firewall, rules []hcloudsdk.FirewallRule) error {
-	setActions, _, err := c.hcloud.Client.Firewall.SetRules(ctx, firewall, hcloudsdk.FirewallSetRulesOpts{
+	setActions, _, err := c.hcloud.Firewall.SetRules(ctx, firewall, hcloudsdk.FirewallSetRulesOpts{
 		Rules: rules,
 	})
 	if err != nil {
@@ -316,6 +374,80 @@ func (c *external) setRules(ctx context.Context, firewall *hcloudsdk.Firewall, r
 	return nil
 }
 
+func getFirewallResources(input []v1alpha1.FirewallApplyTo) ([]hcloudsdk.FirewallResource, error) {
+	resources := make([]hcloudsdk.FirewallResource, 0)
+	for _, a := range input {
+		r, err := a.ToFirewallResource()
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// appliedFirewallResources returns the resources the firewall was last
+// applied to by this Firewall
+func appliedFirewallResources(cr *v1alpha1.Firewall) ([]hcloudsdk.FirewallResource, error) {
+	current := cr.Status.AtProvider.FirewallParameters
+	if current == nil {
+		return nil, nil
+	}
+
+	applied, err := getFirewallResources(current.ApplyTo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert applied firewall resources")
+	}
+
+	return applied, nil
+}
+
+// diffFirewallResources compares the resources wanted with those the live
+// firewall is applied to. Only resources which were previously applied are
+// removed, so that servers which add themselves with firewallIDs are left
+// alone.
+func diffFirewallResources(want, applied, live []hcloudsdk.FirewallResource) (add, remove []hcloudsdk.FirewallResource) {
+	onFirewall := make(map[string]bool, len(live))
+	for _, r := range live {
+		onFirewall[firewallResourceKey(r)] = true
+	}
+
+	wanted := make(map[string]bool, len(want))
+	for _, r := range want {
+		key := firewallResourceKey(r)
+		if !onFirewall[key] && !wanted[key] {
+			add = append(add, r)
+		}
+		wanted[key] = true
+	}
+
+	removed := make(map[string]bool, len(applied))
+	for _, r := range applied {
+		key := firewallResourceKey(r)
+		if onFirewall[key] && !wanted[key] && !removed[key] {
+			remove = append(remove, r)
+		}
+		removed[key] = true
+	}
+
+	return add, remove
+}
+
+// firewallResourceKey identifies a resource by its server ID or label
+// selector, ignoring the resources a label selector currently matches
+func firewallResourceKey(r hcloudsdk.FirewallResource) string {
+	switch {
+	case r.Server != nil:
+		return fmt.Sprintf("%s/%d", r.Type, r.Server.ID)
+	case r.LabelSelector != nil:
+		return fmt.Sprintf("%s/%s", r.Type, r.LabelSelector.Selector)
+	default:
+		return string(r.Type)
+	}
+}
+
 func getFirewallRules(input []v1alpha1.FirewallRules) ([]hcloudsdk.FirewallRule, error) {
 	rules := make([]hcloudsdk.FirewallRule, 0)
 	for _, rule := range input {
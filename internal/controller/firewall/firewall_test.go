@@ -18,11 +18,20 @@ package firewall
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,7 +46,39 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func firewall(id int64) *v1alpha1.Firewall {
+	return &v1alpha1.Firewall{
+		Spec: v1alpha1.FirewallSpec{
+			ForProvider: v1alpha1.FirewallParameters{},
+		},
+		Status: v1alpha1.FirewallStatus{
+			AtProvider: v1alpha1.FirewallObservation{
+				ID:                 id,
+				FirewallParameters: &v1alpha1.FirewallParameters{},
+			},
+		},
+	}
+}
+
 func TestObserve(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	labels := fake.ProviderLabels()
+
+	running := api.AddFirewall(schema.Firewall{Name: "running", Labels: labels})
+	drifted := api.AddFirewall(schema.Firewall{Name: "drifted"})
+	failing := api.AddFirewall(schema.Firewall{Name: "failing"})
+
+	forbidden := api.Forbid(fmt.Sprintf("/firewalls/%d", failing.ID))
+
+	unapplied := firewall(running.ID)
+	unapplied.Spec.ForProvider.ApplyTo = []v1alpha1.FirewallApplyTo{{Type: hcloudsdk.FirewallResourceTypeLabelSelector, Labels: &map[string]string{"env": "prod"}}}
+	unapplied.Status.AtProvider.ApplyTo = unapplied.Spec.ForProvider.ApplyTo
+
+	changed := firewall(running.ID)
+	changed.Spec.ForProvider.Rules = []v1alpha1.FirewallRules{{Direction: hcloudsdk.FirewallRuleDirectionIn, Protocol: hcloudsdk.FirewallRuleProtocolICMP, TargetIPs: []string{"0.0.0.0/0"}}}
+
 	type fields struct {
 		hcloud *hcloud.Client
 	}
@@ -58,7 +99,73 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A firewall which does not exist should be reported as such",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  firewall(999),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A firewall matching the desired state should be up to date",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  firewall(running.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LabelsDrifted": {
+			reason: "A firewall missing the provider labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  firewall(drifted.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"ApplyToDrifted": {
+			reason: "A firewall no longer applied to a wanted resource should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  unapplied,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"RulesChanged": {
+			reason: "A change to the firewall rules should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  changed,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"GetFailed": {
+			reason: "Errors from the API should be returned",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  firewall(failing.ID),
+			},
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				err: forbidden,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -74,3 +181,280 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFirewallRules(t *testing.T) {
+	cidr := func(s string) net.IPNet {
+		_, n, _ := net.ParseCIDR(s)
+		return *n
+	}
+
+	type want struct {
+		rules []hcloudsdk.FirewallRule
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		rules  []v1alpha1.FirewallRules
+		want   want
+	}{
+		"AnyShortcuts": {
+			reason: "The any shortcuts should expand to the IPv4 and IPv6 default routes",
+			rules: []v1alpha1.FirewallRules{
+				{
+					Direction: hcloudsdk.FirewallRuleDirectionIn,
+					Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+					SourceIPs: []string{v1alpha1.FirewallAnyIP},
+					Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(22)},
+				},
+				{
+					Direction:      hcloudsdk.FirewallRuleDirectionOut,
+					Protocol:       hcloudsdk.FirewallRuleProtocolGRE,
+					DestinationIPs: []string{v1alpha1.FirewallAnyIPv6, "10.0.0.0/8"},
+				},
+			},
+			want: want{
+				rules: []hcloudsdk.FirewallRule{
+					{
+						Direction: hcloudsdk.FirewallRuleDirectionIn,
+						Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+						SourceIPs: []net.IPNet{cidr("0.0.0.0/0"), cidr("::/0")},
+						Port:      hcloudsdk.Ptr("22"),
+					},
+					{
+						Direction:      hcloudsdk.FirewallRuleDirectionOut,
+						Protocol:       hcloudsdk.FirewallRuleProtocolGRE,
+						DestinationIPs: []net.IPNet{cidr("::/0"), cidr("10.0.0.0/8")},
+					},
+				},
+			},
+		},
+		"TargetIPs": {
+			reason: "The deprecated target IPs should be used for the rule's direction",
+			rules: []v1alpha1.FirewallRules{
+				{
+					Direction: hcloudsdk.FirewallRuleDirectionOut,
+					Protocol:  hcloudsdk.FirewallRuleProtocolESP,
+					TargetIPs: []string{"10.0.0.0/8"},
+				},
+			},
+			want: want{
+				rules: []hcloudsdk.FirewallRule{
+					{
+						Direction:      hcloudsdk.FirewallRuleDirectionOut,
+						Protocol:       hcloudsdk.FirewallRuleProtocolESP,
+						DestinationIPs: []net.IPNet{cidr("10.0.0.0/8")},
+					},
+				},
+			},
+		},
+		"MissingPort": {
+			reason: "A UDP rule without a port, as older firewalls have, should match every port",
+			rules: []v1alpha1.FirewallRules{
+				{
+					Direction: hcloudsdk.FirewallRuleDirectionIn,
+					Protocol:  hcloudsdk.FirewallRuleProtocolUDP,
+					SourceIPs: []string{v1alpha1.FirewallAnyIP},
+				},
+			},
+			want: want{
+				rules: []hcloudsdk.FirewallRule{
+					{
+						Direction: hcloudsdk.FirewallRuleDirectionIn,
+						Protocol:  hcloudsdk.FirewallRuleProtocolUDP,
+						SourceIPs: []net.IPNet{cidr("0.0.0.0/0"), cidr("::/0")},
+						Port:      hcloudsdk.Ptr("any"),
+					},
+				},
+			},
+		},
+		"PortOutOfRange": {
+			reason: "Ports above 65535 should be rejected",
+			rules: []v1alpha1.FirewallRules{
+				{
+					Direction: hcloudsdk.FirewallRuleDirectionIn,
+					Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+					SourceIPs: []string{v1alpha1.FirewallAnyIP},
+					Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(80), End: hcloudsdk.Ptr(70000)},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("port 70000 must be between 1 and 65535"),
+			},
+		},
+		"WrongDirection": {
+			reason: "An in rule cannot have destination IPs",
+			rules: []v1alpha1.FirewallRules{
+				{
+					Direction:      hcloudsdk.FirewallRuleDirectionIn,
+					Protocol:       hcloudsdk.FirewallRuleProtocolICMP,
+					DestinationIPs: []string{v1alpha1.FirewallAnyIP},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("in rules cannot have destination IPs"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := getFirewallRules(tc.rules)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngetFirewallRules(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rules, got); diff != "" {
+				t.Errorf("\n%s\ngetFirewallRules(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	kept := api.AddServer(schema.Server{Name: "kept"})
+	removed := api.AddServer(schema.Server{Name: "removed"})
+	added := api.AddServer(schema.Server{Name: "added"})
+	attached := api.AddServer(schema.Server{Name: "attached"})
+
+	selector := func(s string) schema.FirewallResource {
+		return schema.FirewallResource{Type: string(hcloudsdk.FirewallResourceTypeLabelSelector), LabelSelector: &schema.FirewallResourceLabelSelector{Selector: s}}
+	}
+
+	existing := api.AddFirewall(schema.Firewall{
+		Name:      "existing",
+		Labels:    fake.ProviderLabels(),
+		AppliedTo: []schema.FirewallResource{fake.ServerResource(kept.ID), fake.ServerResource(removed.ID), fake.ServerResource(attached.ID), selector("env=old")},
+	})
+
+	// The attached server added itself with firewallIDs, so it was never
+	// applied by this Firewall
+	cr := firewall(existing.ID)
+	cr.Status.AtProvider.ApplyTo = []v1alpha1.FirewallApplyTo{
+		{Type: hcloudsdk.FirewallResourceTypeServer, ServerID: hcloudsdk.Ptr(kept.ID)},
+		{Type: hcloudsdk.FirewallResourceTypeServer, ServerID: hcloudsdk.Ptr(removed.ID)},
+		{Type: hcloudsdk.FirewallResourceTypeLabelSelector, Labels: &map[string]string{"env": "old"}},
+	}
+	cr.Spec.ForProvider.ApplyTo = []v1alpha1.FirewallApplyTo{
+		{Type: hcloudsdk.FirewallResourceTypeServer, ServerID: hcloudsdk.Ptr(kept.ID)},
+		{Type: hcloudsdk.FirewallResourceTypeServer, ServerID: hcloudsdk.Ptr(added.ID)},
+		{Type: hcloudsdk.FirewallResourceTypeLabelSelector, Labels: &map[string]string{"env": "new"}},
+	}
+	// The tcp rule has no port, as firewalls created before ports were
+	// validated do, so it matches every port
+	cr.Spec.ForProvider.Rules = []v1alpha1.FirewallRules{
+		{Direction: hcloudsdk.FirewallRuleDirectionIn, Protocol: hcloudsdk.FirewallRuleProtocolICMP, SourceIPs: []string{v1alpha1.FirewallAnyIP}},
+		{Direction: hcloudsdk.FirewallRuleDirectionIn, Protocol: hcloudsdk.FirewallRuleProtocolTCP, SourceIPs: []string{v1alpha1.FirewallAnyIP}},
+	}
+
+	e := external{kube: test.NewMockClient(), hcloud: api.Client()}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+
+	actions := []string{}
+	for _, r := range api.Requests() {
+		if strings.HasPrefix(r, http.MethodPost+" ") {
+			actions = append(actions, r)
+		}
+	}
+	wantActions := []string{
+		fmt.Sprintf("POST /firewalls/%d/actions/set_rules", existing.ID),
+		fmt.Sprintf("POST /firewalls/%d/actions/apply_to_resources", existing.ID),
+		fmt.Sprintf("POST /firewalls/%d/actions/remove_from_resources", existing.ID),
+	}
+	if diff := cmp.Diff(wantActions, actions); diff != "" {
+		t.Errorf("e.Update(...): rules should be set before resources are applied and then removed: -want, +got:\n%s\n", diff)
+	}
+
+	got, _ := api.Firewall(existing.ID)
+	want := []schema.FirewallResource{fake.ServerResource(kept.ID), fake.ServerResource(attached.ID), fake.ServerResource(added.ID), selector("env=new")}
+	if diff := cmp.Diff(want, got.AppliedTo); diff != "" {
+		t.Errorf("e.Update(...): -want applied to, +got applied to:\n%s\n", diff)
+	}
+	if len(got.Rules) != 2 {
+		t.Fatalf("e.Update(...): want 2 rules, got %d", len(got.Rules))
+	}
+	if got.Rules[1].Port == nil || *got.Rules[1].Port != "any" {
+		t.Errorf("e.Update(...): want the portless tcp rule to match any port, got %v", got.Rules[1].Port)
+	}
+}
+
+func TestDiffFirewallResources(t *testing.T) {
+	server := func(id int64) hcloudsdk.FirewallResource {
+		return hcloudsdk.FirewallResource{Type: hcloudsdk.FirewallResourceTypeServer, Server: &hcloudsdk.FirewallResourceServer{ID: id}}
+	}
+	selector := func(s string) hcloudsdk.FirewallResource {
+		return hcloudsdk.FirewallResource{Type: hcloudsdk.FirewallResourceTypeLabelSelector, LabelSelector: &hcloudsdk.FirewallResourceLabelSelector{Selector: s}}
+	}
+
+	type want struct {
+		add    []hcloudsdk.FirewallResource
+		remove []hcloudsdk.FirewallResource
+	}
+
+	cases := map[string]struct {
+		reason  string
+		want    []hcloudsdk.FirewallResource
+		applied []hcloudsdk.FirewallResource
+		live    []hcloudsdk.FirewallResource
+		diff    want
+	}{
+		"UpToDate": {
+			reason:  "Nothing should change when the firewall is applied to the wanted resources",
+			want:    []hcloudsdk.FirewallResource{selector("env=prod"), server(1)},
+			applied: []hcloudsdk.FirewallResource{server(1), selector("env=prod")},
+			live:    []hcloudsdk.FirewallResource{server(1), selector("env=prod")},
+		},
+		"Changed": {
+			reason:  "Only new resources should be added and only unwanted resources removed",
+			want:    []hcloudsdk.FirewallResource{server(2), server(3), selector("env=staging")},
+			applied: []hcloudsdk.FirewallResource{server(1), server(2), selector("env=prod")},
+			live:    []hcloudsdk.FirewallResource{server(1), server(2), selector("env=prod")},
+			diff: want{
+				add:    []hcloudsdk.FirewallResource{server(3), selector("env=staging")},
+				remove: []hcloudsdk.FirewallResource{server(1), selector("env=prod")},
+			},
+		},
+		"AppliedElsewhere": {
+			reason:  "Resources this firewall did not apply, such as servers with firewallIDs, should be left alone",
+			want:    []hcloudsdk.FirewallResource{server(1)},
+			applied: []hcloudsdk.FirewallResource{server(1)},
+			live:    []hcloudsdk.FirewallResource{server(1), server(2)},
+		},
+		"Drifted": {
+			reason:  "Wanted resources removed from the live firewall should be added again",
+			want:    []hcloudsdk.FirewallResource{server(1), server(2)},
+			applied: []hcloudsdk.FirewallResource{server(1), server(2)},
+			live:    []hcloudsdk.FirewallResource{server(1)},
+			diff: want{
+				add: []hcloudsdk.FirewallResource{server(2)},
+			},
+		},
+		"AlreadyRemoved": {
+			reason:  "Previously applied resources which are no longer on the firewall should not be removed again",
+			applied: []hcloudsdk.FirewallResource{server(1)},
+		},
+		"Duplicates": {
+			reason: "A resource wanted twice should only be added once",
+			want:   []hcloudsdk.FirewallResource{server(1), server(1)},
+			diff: want{
+				add: []hcloudsdk.FirewallResource{server(1)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			add, remove := diffFirewallResources(tc.want, tc.applied, tc.live)
+			if diff := cmp.Diff(tc.diff.add, add); diff != "" {
+				t.Errorf("\n%s\ndiffFirewallResources(...): -want add, +got add:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.diff.remove, remove); diff != "" {
+				t.Errorf("\n%s\ndiffFirewallResources(...): -want remove, +got remove:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
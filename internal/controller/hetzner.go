@@ -18,30 +18,51 @@ package controller
 
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/config"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/firewall"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/network"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/placementgroup"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/server"
+	"github.com/mrsimonemms/provider-hetzner/internal/controller/serverpool"
 	"github.com/mrsimonemms/provider-hetzner/internal/controller/volume"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
 // Setup creates all Hetzner controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// the supplied manager. The options are given to every Hetzner client the
+// controllers create.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	for _, setup := range []func(ctrl.Manager, controller.Options) error{
 		config.Setup,
+		serverpool.Setup,
+	} {
+		if err := setup(mgr, o); err != nil {
+			return err
+		}
+	}
+
+	for _, setup := range []func(ctrl.Manager, controller.Options, ...hcloud.Option) error{
 		firewall.Setup,
 		network.Setup,
 		placementgroup.Setup,
 		server.Setup,
 		volume.Setup,
 	} {
-		if err := setup(mgr, o); err != nil {
+		if err := setup(mgr, o, opts...); err != nil {
 			return err
 		}
 	}
-	return nil
+
+	return hcloud.RegisterManagedResourceMetrics(mgr.GetClient(), map[string]func() resource.ManagedList{
+		v1alpha1.FirewallKind:       func() resource.ManagedList { return &v1alpha1.FirewallList{} },
+		v1alpha1.NetworkKind:        func() resource.ManagedList { return &v1alpha1.NetworkList{} },
+		v1alpha1.PlacementGroupKind: func() resource.ManagedList { return &v1alpha1.PlacementGroupList{} },
+		v1alpha1.ServerKind:         func() resource.ManagedList { return &v1alpha1.ServerList{} },
+		v1alpha1.ServerPoolKind:     func() resource.ManagedList { return &v1alpha1.ServerPoolList{} },
+		v1alpha1.VolumeKind:         func() resource.ManagedList { return &v1alpha1.VolumeList{} },
+	})
 }
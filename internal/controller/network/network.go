@@ -18,6 +18,7 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"net"
 
 	"github.com/pkg/errors"
@@ -38,6 +39,7 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -59,7 +61,8 @@ const (
 )
 
 // Setup adds a controller that reconciles Network managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// The options are given to every Hetzner client it creates.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	name := managed.ControllerName(v1alpha1.NetworkGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -73,6 +76,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newServiceFn: hcloud.NewClient,
+			clientOpts:   opts,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
@@ -92,7 +96,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds string) (*hcloud.Client, error)
+	newServiceFn func(creds string, opts ...hcloud.Option) (*hcloud.Client, error)
+	clientOpts   []hcloud.Option
 }
 
 // Connect typically produces an ExternalClient by:
@@ -115,21 +120,31 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
+	cd, err := pc.GetCredentials(cr.Spec.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(string(data))
+	svc, err := c.newServiceFn(string(data), append([]hcloud.Option{
+		hcloud.WithDefaultLabels(pc.Spec.Labels.DefaultLabels(mg)),
+		hcloud.WithLabelMode(pc.Spec.Labels.GetMode()),
+		hcloud.WithProviderConfig(pc.Name, cr.Spec.Project),
+	}, c.clientOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{
+	return tracing.NewExternalClient(v1alpha1.NetworkKind, func(mg resource.Managed) int64 {
+		return mg.(*v1alpha1.Network).Status.AtProvider.ID
+	}, &external{
 		kube:   c.kube,
 		hcloud: svc,
-	}, nil
+	}), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -145,7 +160,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotNetwork)
 	}
 
-	network, _, err := c.hcloud.Client.Network.GetByID(ctx, cr.Status.AtProvider.ID)
+	network, _, err := c.hcloud.Network.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
@@ -157,7 +172,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: cr.IsUpToDate(),
+		ResourceUpToDate: cr.IsUpToDate() && c.hcloud.LabelsUpToDate(network.Labels, cr.Spec.ForProvider.Labels.Map()),
 	}, nil
 }
 
@@ -201,12 +216,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		})
 	}
 
-	network, _, err := c.hcloud.Client.Network.Create(ctx, hcloudsdk.NetworkCreateOpts{
+	labels, err := c.hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels.Map())
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	network, _, err := c.hcloud.Network.Create(ctx, hcloudsdk.NetworkCreateOpts{
 		Name:                  cr.ObjectMeta.Name,
 		IPRange:               ipRange,
 		Subnets:               subnets,
 		Routes:                routes,
-		Labels:                hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels),
+		Labels:                labels,
 		ExposeRoutesToVSwitch: cr.Spec.ForProvider.ExposeRoutesToVSwitch,
 	})
 	if err != nil {
@@ -228,18 +248,26 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotNetwork)
 	}
 
-	network, _, err := c.hcloud.Client.Network.GetByID(ctx, cr.Status.AtProvider.ID)
+	network, _, err := c.hcloud.Network.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to get network")
 	}
+	if network == nil {
+		return managed.ExternalUpdate{}, fmt.Errorf("unknown network")
+	}
 
 	current := *cr.Status.AtProvider.NetworkParameters // What we have
 	target := cr.Spec.ForProvider                      // What we want
 
+	labels, err := c.hcloud.UpdateLabels(network.Labels, target.Labels.Map())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
 	// Update the network
-	if _, _, err := c.hcloud.Client.Network.Update(ctx, network, hcloudsdk.NetworkUpdateOpts{
+	if _, _, err := c.hcloud.Network.Update(ctx, network, hcloudsdk.NetworkUpdateOpts{
 		ExposeRoutesToVSwitch: &target.ExposeRoutesToVSwitch,
-		Labels:                hcloud.ApplyDefaultLabels(target.Labels),
+		Labels:                labels,
 	}); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to perform network update")
 	}
@@ -251,7 +279,7 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 			return managed.ExternalUpdate{}, errors.Wrap(err, errIPRangeParseFailed)
 		}
 
-		action, _, err := c.hcloud.Client.Network.ChangeIPRange(ctx, network, hcloudsdk.NetworkChangeIPRangeOpts{
+		action, _, err := c.hcloud.Network.ChangeIPRange(ctx, network, hcloudsdk.NetworkChangeIPRangeOpts{
 			IPRange: ipRange,
 		})
 		if err != nil {
@@ -284,7 +312,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(xpv1.Deleting())
 
-	_, err := c.hcloud.Client.Network.Delete(ctx, &hcloudsdk.Network{
+	_, err := c.hcloud.Network.Delete(ctx, &hcloudsdk.Network{
 		ID: cr.Status.AtProvider.ID,
 	})
 	if err != nil {
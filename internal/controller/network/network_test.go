@@ -18,11 +18,23 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,7 +49,130 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func network(id int64) *v1alpha1.Network {
+	return &v1alpha1.Network{
+		Spec: v1alpha1.NetworkSpec{
+			ForProvider: v1alpha1.NetworkParameters{IPRange: "10.0.0.0/16"},
+		},
+		Status: v1alpha1.NetworkStatus{
+			AtProvider: v1alpha1.NetworkObservation{
+				ID:                id,
+				NetworkParameters: &v1alpha1.NetworkParameters{IPRange: "10.0.0.0/16"},
+			},
+		},
+	}
+}
+
+func TestConnect(t *testing.T) {
+	// Each project's credentials are a secret holding a token of the same name
+	credentials := func(name string) apisv1alpha1.ProviderCredentials {
+		return apisv1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: name, Namespace: "default"},
+					Key:             "token",
+				},
+			},
+		}
+	}
+
+	pc := apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: credentials("default-token"),
+			Projects: []apisv1alpha1.ProjectCredentials{
+				{Name: "staging", Credentials: credentials("staging-token")},
+			},
+		},
+	}
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *apisv1alpha1.ProviderConfig:
+				pc.DeepCopyInto(o)
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"token": []byte(key.Name)}
+			}
+			return nil
+		},
+	}
+
+	withProject := func(project string) *v1alpha1.Network {
+		cr := network(0)
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		if project != "" {
+			cr.Spec.Project = hcloudsdk.Ptr(project)
+		}
+		return cr
+	}
+
+	type want struct {
+		token string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   want
+	}{
+		"DefaultCredentials": {
+			reason: "A network without a project should connect with the default credentials",
+			mg:     withProject(""),
+			want:   want{token: "default-token"},
+		},
+		"ProjectCredentials": {
+			reason: "A network with a project should connect with that project's credentials",
+			mg:     withProject("staging"),
+			want:   want{token: "staging-token"},
+		},
+		"MissingProject": {
+			reason: "A network with a project the ProviderConfig doesn't have should fail to connect",
+			mg:     withProject("production"),
+			want:   want{err: errors.Wrap(errors.New(`project "production" not found in ProviderConfig default`), errGetCreds)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var token string
+			c := &connector{
+				kube:  kube,
+				usage: resource.TrackerFn(func(context.Context, resource.Managed) error { return nil }),
+				newServiceFn: func(creds string, opts ...hcloud.Option) (*hcloud.Client, error) {
+					token = creds
+					return hcloud.NewClient(creds, opts...)
+				},
+			}
+
+			_, err := c.Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, token); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want token, +got token:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestObserve(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	labels := fake.ProviderLabels()
+
+	running := api.AddNetwork(schema.Network{Name: "running", Labels: labels})
+	drifted := api.AddNetwork(schema.Network{Name: "drifted"})
+	failing := api.AddNetwork(schema.Network{Name: "failing"})
+
+	forbidden := api.Forbid(fmt.Sprintf("/networks/%d", failing.ID))
+
+	changed := network(running.ID)
+	changed.Spec.ForProvider.IPRange = "10.1.0.0/16"
+
 	type fields struct {
 		hcloud *hcloud.Client
 	}
@@ -58,7 +193,62 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A network which does not exist should be reported as such",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  network(999),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A network matching the desired state should be up to date",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  network(running.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LabelsDrifted": {
+			reason: "A network missing the provider labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  network(drifted.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"IPRangeChanged": {
+			reason: "A change to the IP range should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  changed,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"GetFailed": {
+			reason: "Errors from the API should be returned",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  network(failing.ID),
+			},
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				err: forbidden,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -18,8 +18,10 @@ package placementgroup
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +39,7 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -47,10 +50,13 @@ const (
 	errGetCreds          = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	reasonReplaced event.Reason = "ReplacedExternalResource"
 )
 
 // Setup adds a controller that reconciles PlacementGroup managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// The options are given to every Hetzner client it creates.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	name := managed.ControllerName(v1alpha1.PlacementGroupGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -64,6 +70,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newServiceFn: hcloud.NewClient,
+			clientOpts:   opts,
+			recorder:     event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
@@ -83,7 +91,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds string) (*hcloud.Client, error)
+	newServiceFn func(creds string, opts ...hcloud.Option) (*hcloud.Client, error)
+	clientOpts   []hcloud.Option
+	recorder     event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -106,28 +116,40 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
+	cd, err := pc.GetCredentials(cr.Spec.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(string(data))
+	svc, err := c.newServiceFn(string(data), append([]hcloud.Option{
+		hcloud.WithDefaultLabels(pc.Spec.Labels.DefaultLabels(mg)),
+		hcloud.WithLabelMode(pc.Spec.Labels.GetMode()),
+		hcloud.WithProviderConfig(pc.Name, cr.Spec.Project),
+	}, c.clientOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{
-		kube:   c.kube,
-		hcloud: svc,
-	}, nil
+	return tracing.NewExternalClient(v1alpha1.PlacementGroupKind, func(mg resource.Managed) int64 {
+		return mg.(*v1alpha1.PlacementGroup).Status.AtProvider.ID
+	}, &external{
+		kube:     c.kube,
+		hcloud:   svc,
+		recorder: c.recorder,
+	}), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube   client.Client
-	hcloud *hcloud.Client
+	kube     client.Client
+	hcloud   *hcloud.Client
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -136,7 +158,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotPlacementGroup)
 	}
 
-	placementGroup, _, err := c.hcloud.Client.PlacementGroup.GetByID(ctx, cr.Status.AtProvider.ID)
+	placementGroup, _, err := c.hcloud.PlacementGroup.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
@@ -144,11 +166,22 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
+	if cr.NeedsReplacement() {
+		// The placement group is replaced by Update, so that it is never
+		// deleted just by being observed
+		cr.SetConditions(apisv1alpha1.ReplacementPending())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+	}
+	if cr.GetCondition(apisv1alpha1.TypeReplacementPending).Status == corev1.ConditionTrue {
+		cr.SetConditions(apisv1alpha1.ReplacementNotPending())
+	}
+
+	cr.Status.AtProvider.Servers = placementGroup.Servers
 	cr.SetConditions(xpv1.Available())
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: cr.IsUpToDate(),
+		ResourceUpToDate: cr.IsUpToDate() && c.hcloud.LabelsUpToDate(placementGroup.Labels, cr.Spec.ForProvider.Labels.Map()),
 	}, nil
 }
 
@@ -160,9 +193,14 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.Status.SetConditions(xpv1.Creating())
 
-	placementGroup, _, err := c.hcloud.Client.PlacementGroup.Create(ctx, hcloudsdk.PlacementGroupCreateOpts{
+	labels, err := c.hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels.Map())
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	placementGroup, _, err := c.hcloud.PlacementGroup.Create(ctx, hcloudsdk.PlacementGroupCreateOpts{
 		Name:   cr.ObjectMeta.Name,
-		Labels: hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels),
+		Labels: labels,
 		Type:   cr.Spec.ForProvider.Type,
 	})
 	if err != nil {
@@ -184,16 +222,28 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotPlacementGroup)
 	}
 
-	placementGroup, _, err := c.hcloud.Client.PlacementGroup.GetByID(ctx, cr.Status.AtProvider.ID)
+	placementGroup, _, err := c.hcloud.PlacementGroup.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to get placement group")
 	}
+	if placementGroup == nil {
+		return managed.ExternalUpdate{}, fmt.Errorf("unknown placement group")
+	}
+
+	if cr.NeedsReplacement() {
+		return managed.ExternalUpdate{}, c.replace(ctx, cr)
+	}
 
 	target := cr.Spec.ForProvider
 
-	// Update the network
-	if _, _, err := c.hcloud.Client.PlacementGroup.Update(ctx, placementGroup, hcloudsdk.PlacementGroupUpdateOpts{
-		Labels: hcloud.ApplyDefaultLabels(target.Labels),
+	labels, err := c.hcloud.UpdateLabels(placementGroup.Labels, target.Labels.Map())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// Update the placement group
+	if _, _, err := c.hcloud.PlacementGroup.Update(ctx, placementGroup, hcloudsdk.PlacementGroupUpdateOpts{
+		Labels: labels,
 	}); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to perform placement group update")
 	}
@@ -214,7 +264,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(xpv1.Deleting())
 
-	_, err := c.hcloud.Client.PlacementGroup.Delete(ctx, &hcloudsdk.PlacementGroup{
+	_, err := c.hcloud.PlacementGroup.Delete(ctx, &hcloudsdk.PlacementGroup{
 		ID: cr.Status.AtProvider.ID,
 	})
 	if err != nil {
@@ -223,3 +273,22 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	return nil
 }
+
+// replace deletes a placement group whose create-only parameters have
+// changed, so that it is created again with the new parameters
+func (c *external) replace(ctx context.Context, cr *v1alpha1.PlacementGroup) error {
+	id := cr.Status.AtProvider.ID
+	if err := c.Delete(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to delete placement group for replacement")
+	}
+
+	c.recorder.Event(cr, event.Normal(reasonReplaced, fmt.Sprintf("Deleted placement group %d to replace it as a create-only field changed", id)))
+
+	cr.Status.AtProvider = v1alpha1.PlacementGroupObservation{}
+	cr.SetConditions(xpv1.Creating(), apisv1alpha1.ReplacementNotPending())
+	if err := c.kube.Status().Update(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to save status")
+	}
+
+	return nil
+}
@@ -18,11 +18,18 @@ package placementgroup
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,7 +44,36 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func placementGroup(id int64) *v1alpha1.PlacementGroup {
+	return &v1alpha1.PlacementGroup{
+		Spec: v1alpha1.PlacementGroupSpec{
+			ForProvider: v1alpha1.PlacementGroupParameters{},
+		},
+		Status: v1alpha1.PlacementGroupStatus{
+			AtProvider: v1alpha1.PlacementGroupObservation{
+				ID:                       id,
+				PlacementGroupParameters: &v1alpha1.PlacementGroupParameters{},
+			},
+		},
+	}
+}
+
 func TestObserve(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	labels := fake.ProviderLabels()
+
+	running := api.AddPlacementGroup(schema.PlacementGroup{Name: "running", Labels: labels})
+	drifted := api.AddPlacementGroup(schema.PlacementGroup{Name: "drifted"})
+	failing := api.AddPlacementGroup(schema.PlacementGroup{Name: "failing"})
+	members := api.AddPlacementGroup(schema.PlacementGroup{Name: "members", Labels: labels, Servers: []int64{11, 12}})
+
+	forbidden := api.Forbid(fmt.Sprintf("/placement_groups/%d", failing.ID))
+
+	changed := placementGroup(running.ID)
+	changed.Spec.ForProvider.Labels = apisv1alpha1.Labels{"environment": "prod"}
+
 	type fields struct {
 		hcloud *hcloud.Client
 	}
@@ -48,8 +84,9 @@ func TestObserve(t *testing.T) {
 	}
 
 	type want struct {
-		o   managed.ExternalObservation
-		err error
+		o       managed.ExternalObservation
+		servers []int64
+		err     error
 	}
 
 	cases := map[string]struct {
@@ -58,7 +95,74 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A placement group which does not exist should be reported as such",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  placementGroup(999),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A placement group matching the desired state should be up to date",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  placementGroup(running.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Members": {
+			reason: "The servers in a placement group should be listed in its status",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  placementGroup(members.ID),
+			},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				servers: []int64{11, 12},
+			},
+		},
+		"LabelsDrifted": {
+			reason: "A placement group missing the provider labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  placementGroup(drifted.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"SpecLabelsChanged": {
+			reason: "A change to the desired labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  changed,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"GetFailed": {
+			reason: "Errors from the API should be returned",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  placementGroup(failing.ID),
+			},
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				err: forbidden,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -71,6 +175,9 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.servers, tc.args.mg.(*v1alpha1.PlacementGroup).Status.AtProvider.Servers, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want servers, +got servers:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// Connection detail keys published in addition to the standard endpoint,
+// username, password and port
+const (
+	connectionKeyID          = "id"
+	connectionKeyName        = "name"
+	connectionKeyDatacenter  = "datacenter"
+	connectionKeyIPv4        = "ipv4"
+	connectionKeyIPv6        = "ipv6"
+	connectionKeyIPv6Network = "ipv6Network"
+	connectionKeyPrivateIPs  = "privateIPs"
+	connectionKeyKnownHosts  = "knownHosts"
+	connectionKeyPrivateKey  = "privateKey"
+	connectionKeyPublicKey   = "publicKey"
+
+	sshPort = 22
+)
+
+// connectionDetails returns the details needed to reach the server. The root
+// password is only known when the server is created, so Create adds it and
+// the details published by each Observe leave it in place.
+func connectionDetails(server *hcloudsdk.Server, hostKey ssh.PublicKey) managed.ConnectionDetails {
+	conn := managed.ConnectionDetails{
+		xpv1.ResourceCredentialsSecretUserKey: []byte("root"),
+		xpv1.ResourceCredentialsSecretPortKey: []byte(strconv.Itoa(sshPort)),
+		connectionKeyID:                       []byte(strconv.FormatInt(server.ID, 10)),
+		connectionKeyName:                     []byte(server.Name),
+	}
+	if server.Datacenter != nil {
+		conn[connectionKeyDatacenter] = []byte(server.Datacenter.Name)
+	}
+
+	addresses := serverAddresses(server)
+	if len(addresses) > 0 {
+		conn[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(addresses[0])
+	}
+
+	if hasIPv4(server) {
+		conn[connectionKeyIPv4] = []byte(server.PublicNet.IPv4.IP.String())
+	}
+	if hasIPv6(server) {
+		conn[connectionKeyIPv6] = []byte(ipv6Address(server.PublicNet.IPv6.Network).String())
+		conn[connectionKeyIPv6Network] = []byte(server.PublicNet.IPv6.Network.String())
+	}
+	if ips := privateIPs(server); len(ips) > 0 {
+		conn[connectionKeyPrivateIPs] = []byte(strings.Join(ips, ","))
+	}
+
+	// The host key is only known when the provider generated it, rather than
+	// trusting whichever key the server first offers
+	if hostKey != nil && len(addresses) > 0 {
+		// knownhosts.Line brackets IPv6 addresses, which OpenSSH only
+		// expects when they are paired with a port other than 22
+		conn[connectionKeyKnownHosts] = append([]byte(strings.Join(addresses, ",")+" "), ssh.MarshalAuthorizedKey(hostKey)...)
+	}
+
+	return conn
+}
+
+// serverAddresses lists the addresses the server can be reached on, most
+// preferred first: the public IPv4, the public IPv6 and then any private IPs
+func serverAddresses(server *hcloudsdk.Server) []string {
+	addresses := make([]string, 0)
+	if hasIPv4(server) {
+		addresses = append(addresses, server.PublicNet.IPv4.IP.String())
+	}
+	if hasIPv6(server) {
+		addresses = append(addresses, ipv6Address(server.PublicNet.IPv6.Network).String())
+	}
+
+	return append(addresses, privateIPs(server)...)
+}
+
+func hasIPv4(server *hcloudsdk.Server) bool {
+	return !server.PublicNet.IPv4.IsUnspecified()
+}
+
+func hasIPv6(server *hcloudsdk.Server) bool {
+	return !server.PublicNet.IPv6.IsUnspecified() && server.PublicNet.IPv6.Network != nil
+}
+
+func privateIPs(server *hcloudsdk.Server) []string {
+	ips := make([]string, 0, len(server.PrivateNet))
+	for _, n := range server.PrivateNet {
+		if n.IP != nil {
+			ips = append(ips, n.IP.String())
+		}
+	}
+
+	return ips
+}
+
+// ipv6Address returns the first address in the server's IPv6 network, which
+// Hetzner's images configure on the primary interface
+func ipv6Address(network *net.IPNet) net.IP {
+	ip := make(net.IP, len(network.IP))
+	copy(ip, network.IP)
+	ip[len(ip)-1] |= 1
+
+	return ip
+}
+
+// generateSSHKey returns a new ed25519 key pair, with the public key in
+// authorized_keys format and the private key PEM encoded in OpenSSH format
+func generateSSHKey(comment string) (string, []byte, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to generate ssh key")
+	}
+
+	publicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to encode public ssh key")
+	}
+
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to encode private ssh key")
+	}
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))) + " " + comment
+
+	return authorizedKey, pem.EncodeToMemory(block), nil
+}
+
+// hostKeyUserData returns a cloud-config part which installs the generated
+// ed25519 host key, so that the server's host key is known before it boots
+func hostKeyUserData(publicKey string, privateKey []byte) userDataPart {
+	var b strings.Builder
+	b.WriteString("#cloud-config\nssh_keys:\n  ed25519_private: |\n")
+	for _, line := range strings.Split(strings.TrimSpace(string(privateKey)), "\n") {
+		b.WriteString("    " + line + "\n")
+	}
+	b.WriteString("  ed25519_public: " + publicKey + "\n")
+
+	return userDataPart{contentType: "text/cloud-config", content: b.String()}
+}
+
+// parseHostKey returns the generated host key recorded in the status, if any
+func parseHostKey(hostKey string) ssh.PublicKey {
+	if hostKey == "" {
+		return nil
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return nil
+	}
+
+	return key
+}
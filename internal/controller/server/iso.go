@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+)
+
+// isoUpToDate reports whether the ISO attached to the server is the one
+// wanted, which is given by name or ID
+func isoUpToDate(want *string, attached *hcloudsdk.ISO) bool {
+	if want == nil || attached == nil {
+		return want == nil && attached == nil
+	}
+
+	return *want == attached.Name || *want == strconv.FormatInt(attached.ID, 10)
+}
+
+// updateISO attaches the wanted ISO, which replaces any already attached, or
+// detaches the ISO when none is wanted
+func (c *external) updateISO(ctx context.Context, server *hcloudsdk.Server, want *string) error {
+	var action *hcloudsdk.Action
+
+	if want == nil {
+		a, _, err := c.hcloud.Server.DetachISO(ctx, server)
+		if err != nil {
+			return errors.Wrap(err, "failed to detach iso")
+		}
+		action = a
+	} else {
+		iso, _, err := c.hcloud.ISO.Get(ctx, *want)
+		if err != nil {
+			return errors.Wrap(err, "failed to get iso")
+		}
+		if iso == nil {
+			return fmt.Errorf("unknown iso %q", *want)
+		}
+		if iso.Architecture != nil && server.ServerType != nil && *iso.Architecture != server.ServerType.Architecture {
+			return fmt.Errorf("iso %q is for %s servers, not %s", iso.Name, *iso.Architecture, server.ServerType.Architecture)
+		}
+
+		a, _, err := c.hcloud.Server.AttachISO(ctx, server, iso)
+		if err != nil {
+			return errors.Wrap(err, "failed to attach iso")
+		}
+		action = a
+	}
+
+	if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
+		return errors.Wrap(err, "error waiting for iso to change")
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+func TestUpdateISO(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	x86 := "ubuntu-24.04-live-server-amd64.iso"
+	arm := "ubuntu-24.04-live-server-arm64.iso"
+
+	type want struct {
+		attached *string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		attached *string
+		iso      *string
+		want     want
+	}{
+		"AttachByName": {
+			reason: "An ISO should be attached by its name",
+			iso:    &x86,
+			want: want{
+				attached: &x86,
+			},
+		},
+		"AttachByID": {
+			reason: "An ISO should be attached by its ID",
+			iso:    hcloudsdk.Ptr("9032"),
+			want: want{
+				attached: &x86,
+			},
+		},
+		"Detach": {
+			reason:   "An ISO which is no longer wanted should be detached",
+			attached: &x86,
+			want:     want{},
+		},
+		"Unknown": {
+			reason: "An ISO which does not exist should not be attached",
+			iso:    hcloudsdk.Ptr("missing.iso"),
+			want: want{
+				err: errors.New(`unknown iso "missing.iso"`),
+			},
+		},
+		"WrongArchitecture": {
+			reason:   "An ISO for another architecture should not replace the attached ISO",
+			attached: &x86,
+			iso:      &arm,
+			want: want{
+				attached: &x86,
+				err:      errors.New(`iso "ubuntu-24.04-live-server-arm64.iso" is for arm servers, not x86`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			seed := schema.Server{Name: name}
+			if tc.attached != nil {
+				seed.ISO = &schema.ISO{ID: 9032, Name: *tc.attached}
+			}
+			seeded := api.AddServer(seed)
+
+			e := external{hcloud: api.Client()}
+			server := fake.GetServer(t, e.hcloud, seeded.ID)
+
+			err := e.updateISO(context.Background(), server, tc.iso)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.updateISO(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+
+			server = fake.GetServer(t, e.hcloud, seeded.ID)
+			if !isoUpToDate(tc.want.attached, server.ISO) {
+				t.Errorf("\n%s\ne.updateISO(...): want ISO %v attached, got %v", tc.reason, tc.want.attached, server.ISO)
+			}
+		})
+	}
+}
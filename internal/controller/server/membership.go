@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// A membershipDiff is the firewalls, networks or volumes to add to and
+// remove from a server.
+type membershipDiff struct {
+	add    []int64
+	remove []int64
+}
+
+func (d membershipDiff) empty() bool {
+	return len(d.add) == 0 && len(d.remove) == 0
+}
+
+// diffMembership compares the IDs wanted with those on the live server. Only
+// IDs which were previously wanted are removed, so that firewalls applied by
+// a Firewall's applyTo or label selector and volumes attached by a Volume are
+// left alone.
+func diffMembership(want, applied, live []int64) membershipDiff {
+	var d membershipDiff
+	for _, id := range want {
+		if !slices.Contains(live, id) && !slices.Contains(d.add, id) {
+			d.add = append(d.add, id)
+		}
+	}
+	for _, id := range applied {
+		if slices.Contains(live, id) && !slices.Contains(want, id) && !slices.Contains(d.remove, id) {
+			d.remove = append(d.remove, id)
+		}
+	}
+	return d
+}
+
+// memberships are the changes needed to a server's firewalls, networks and
+// volumes
+type memberships struct {
+	firewalls membershipDiff
+	networks  membershipDiff
+	volumes   membershipDiff
+}
+
+func (m memberships) empty() bool {
+	return m.count() == 0
+}
+
+func (m memberships) count() int {
+	return len(m.firewalls.add) + len(m.firewalls.remove) +
+		len(m.networks.add) + len(m.networks.remove) +
+		len(m.volumes.add) + len(m.volumes.remove)
+}
+
+// diffMemberships compares the firewalls, networks and volumes wanted with
+// the live server
+func diffMemberships(cr *v1alpha1.Server, server *hcloudsdk.Server) memberships {
+	target := cr.Spec.ForProvider
+	var applied v1alpha1.ServerParameters
+	if current := cr.Status.AtProvider.ServerParameters; current != nil {
+		applied = *current
+	}
+
+	var firewalls, networks, volumes []int64
+	for _, f := range server.PublicNet.Firewalls {
+		firewalls = append(firewalls, f.Firewall.ID)
+	}
+	for _, n := range server.PrivateNet {
+		if n.Network != nil {
+			networks = append(networks, n.Network.ID)
+		}
+	}
+	for _, v := range server.Volumes {
+		volumes = append(volumes, v.ID)
+	}
+
+	return memberships{
+		firewalls: diffMembership(target.FirewallIDs, applied.FirewallIDs, firewalls),
+		networks:  diffMembership(target.NetworkIDs, applied.NetworkIDs, networks),
+		volumes:   diffMembership(target.VolumeIDs, applied.VolumeIDs, volumes),
+	}
+}
+
+// updateMemberships adds the server to and removes it from firewalls,
+// networks and volumes. Every change is attempted, and those which failed
+// are returned together.
+func (c *external) updateMemberships(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server) error {
+	diff := diffMemberships(cr, server)
+	resource := []hcloudsdk.FirewallResource{{
+		Type:   hcloudsdk.FirewallResourceTypeServer,
+		Server: &hcloudsdk.FirewallResourceServer{ID: server.ID},
+	}}
+
+	var failed []string
+	fail := func(err error, format string, id int64) {
+		failed = append(failed, errors.Wrapf(err, format, id).Error())
+	}
+
+	for _, id := range diff.firewalls.remove {
+		actions, _, err := c.hcloud.Firewall.RemoveResources(ctx, &hcloudsdk.Firewall{ID: id}, resource)
+		if err == nil {
+			err = c.waitForActions(ctx, actions...)
+		}
+		if err != nil {
+			fail(err, "failed to remove firewall %d", id)
+		}
+	}
+	for _, id := range diff.firewalls.add {
+		actions, _, err := c.hcloud.Firewall.ApplyResources(ctx, &hcloudsdk.Firewall{ID: id}, resource)
+		if err == nil {
+			err = c.waitForActions(ctx, actions...)
+		}
+		if err != nil {
+			fail(err, "failed to apply firewall %d", id)
+		}
+	}
+
+	for _, id := range diff.networks.remove {
+		action, _, err := c.hcloud.Server.DetachFromNetwork(ctx, server, hcloudsdk.ServerDetachFromNetworkOpts{
+			Network: &hcloudsdk.Network{ID: id},
+		})
+		if err == nil {
+			err = c.waitForActions(ctx, action)
+		}
+		if err != nil {
+			fail(err, "failed to detach from network %d", id)
+		}
+	}
+	for _, id := range diff.networks.add {
+		action, _, err := c.hcloud.Server.AttachToNetwork(ctx, server, hcloudsdk.ServerAttachToNetworkOpts{
+			Network: &hcloudsdk.Network{ID: id},
+		})
+		if err == nil {
+			err = c.waitForActions(ctx, action)
+		}
+		if err != nil {
+			fail(err, "failed to attach to network %d", id)
+		}
+	}
+
+	for _, id := range diff.volumes.remove {
+		action, _, err := c.hcloud.Volume.Detach(ctx, &hcloudsdk.Volume{ID: id})
+		if err == nil {
+			err = c.waitForActions(ctx, action)
+		}
+		if err != nil {
+			fail(err, "failed to detach volume %d", id)
+		}
+	}
+	for _, id := range diff.volumes.add {
+		action, _, err := c.hcloud.Volume.AttachWithOpts(ctx, &hcloudsdk.Volume{ID: id}, hcloudsdk.VolumeAttachOpts{
+			Server:    server,
+			Automount: &cr.Spec.ForProvider.AutoMount,
+		})
+		if err == nil {
+			err = c.waitForActions(ctx, action)
+		}
+		if err != nil {
+			fail(err, "failed to attach volume %d", id)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d membership changes failed: %s", len(failed), diff.count(), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+func (c *external) waitForActions(ctx context.Context, actions ...*hcloudsdk.Action) error {
+	for _, action := range actions {
+		if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
+			return errors.Wrap(err, "error completing action")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+func TestDiffMembership(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		want    []int64
+		applied []int64
+		live    []int64
+		diff    membershipDiff
+	}{
+		"UpToDate": {
+			reason:  "Nothing should change when the server has what is wanted",
+			want:    []int64{1, 2},
+			applied: []int64{1, 2},
+			live:    []int64{2, 1},
+		},
+		"Added": {
+			reason:  "IDs missing from the server should be added",
+			want:    []int64{1, 2},
+			applied: []int64{1},
+			live:    []int64{1},
+			diff:    membershipDiff{add: []int64{2}},
+		},
+		"Removed": {
+			reason:  "IDs no longer wanted should be removed",
+			want:    []int64{1},
+			applied: []int64{1, 2},
+			live:    []int64{1, 2},
+			diff:    membershipDiff{remove: []int64{2}},
+		},
+		"ManagedElsewhere": {
+			reason:  "IDs which were never wanted should be left alone",
+			want:    []int64{1},
+			applied: []int64{1},
+			live:    []int64{1, 3},
+		},
+		"AlreadyRemoved": {
+			reason:  "IDs no longer on the server should not be removed again",
+			applied: []int64{1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := diffMembership(tc.want, tc.applied, tc.live)
+			if diff := cmp.Diff(tc.diff, got, cmp.AllowUnexported(membershipDiff{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ndiffMembership(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateMemberships(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	firewall := api.AddFirewall(schema.Firewall{Name: "firewall"})
+	network := api.AddNetwork(schema.Network{Name: "network", IPRange: "10.0.0.0/16"})
+	volume := api.AddVolume(schema.Volume{Name: "volume", Size: 10})
+	seeded := api.AddServer(schema.Server{Name: "example"})
+
+	// Applied by a Firewall rather than the server
+	other := api.AddFirewall(schema.Firewall{Name: "other", AppliedTo: []schema.FirewallResource{fake.ServerResource(seeded.ID)}})
+
+	e := external{hcloud: api.Client()}
+	get := func() *hcloudsdk.Server {
+		t.Helper()
+		return fake.GetServer(t, e.hcloud, seeded.ID)
+	}
+
+	cr := server(seeded.ID, true, true)
+	cr.Spec.ForProvider.FirewallIDs = []int64{firewall.ID}
+	cr.Spec.ForProvider.NetworkIDs = []int64{network.ID}
+	cr.Spec.ForProvider.VolumeIDs = []int64{volume.ID}
+
+	if err := e.updateMemberships(context.Background(), cr, get()); err != nil {
+		t.Fatalf("e.updateMemberships(...): %s", err)
+	}
+	if diff := diffMemberships(cr, get()); !diff.empty() {
+		t.Errorf("e.updateMemberships(...): want the server added to everything, still need %+v", diff)
+	}
+
+	// Everything is removed again, leaving the other firewall, and a change
+	// which fails is reported without stopping the others
+	cr.Status.AtProvider.ServerParameters.FirewallIDs = cr.Spec.ForProvider.FirewallIDs
+	cr.Status.AtProvider.ServerParameters.NetworkIDs = cr.Spec.ForProvider.NetworkIDs
+	cr.Status.AtProvider.ServerParameters.VolumeIDs = cr.Spec.ForProvider.VolumeIDs
+	cr.Spec.ForProvider = v1alpha1.ServerParameters{NetworkIDs: []int64{999}}
+
+	err := e.updateMemberships(context.Background(), cr, get())
+	if err == nil || !strings.HasPrefix(err.Error(), "1 of 4 membership changes failed: failed to attach to network 999") {
+		t.Errorf("e.updateMemberships(...): want the unknown network reported, got %v", err)
+	}
+
+	s := get()
+	var firewalls []int64
+	for _, f := range s.PublicNet.Firewalls {
+		firewalls = append(firewalls, f.Firewall.ID)
+	}
+	if !slices.Equal(firewalls, []int64{other.ID}) {
+		t.Errorf("e.updateMemberships(...): want only firewall %d applied, got %v", other.ID, firewalls)
+	}
+	if len(s.PrivateNet) != 0 || len(s.Volumes) != 0 {
+		t.Errorf("e.updateMemberships(...): want the network and volume removed, got %v and %v", s.PrivateNet, s.Volumes)
+	}
+}
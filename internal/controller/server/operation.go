@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// operationRequested returns whether an operation has been requested with
+// the annotation and not yet run
+func operationRequested(cr *v1alpha1.Server) bool {
+	_, ok := cr.GetAnnotations()[apisv1alpha1.AnnotationKeyOperation]
+	return ok
+}
+
+// operate runs the operation requested with the annotation, if any, and
+// removes the annotation so that it is only run once. The outcome is
+// recorded in the status rather than returned, so that a failed operation
+// is not retried. A new root password is returned as a connection detail.
+//
+// The status is saved before the annotation is removed, so the outcome is
+// never lost. The operation is run again if the status can't be saved, which
+// also publishes a password that matches the server. If the annotation can't
+// be removed, only its removal is retried.
+func (c *external) operate(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server) (managed.ConnectionDetails, error) {
+	value, ok := cr.GetAnnotations()[apisv1alpha1.AnnotationKeyOperation]
+	if !ok {
+		return nil, nil
+	}
+
+	operation := apisv1alpha1.ServerOperation(value)
+
+	var conn managed.ConnectionDetails
+	if last := cr.Status.AtProvider.LastOperation; last == nil || last.AnnotationRemoved || last.Operation != operation {
+		var err error
+		conn, err = c.runOperation(ctx, operation, server)
+
+		status := &apisv1alpha1.OperationStatus{
+			Operation: operation,
+			Result:    apisv1alpha1.OperationSucceeded,
+			Time:      metav1.Now(),
+		}
+		if err != nil {
+			status.Result = apisv1alpha1.OperationFailed
+			status.Message = err.Error()
+		}
+		cr.Status.AtProvider.LastOperation = status
+		if err := c.kube.Status().Update(ctx, cr); err != nil {
+			return nil, errors.Wrap(err, "failed to save operation status")
+		}
+	}
+
+	// The patch is made to a copy, so that the status set by this reconcile
+	// is kept
+	patched := cr.DeepCopy()
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, apisv1alpha1.AnnotationKeyOperation)
+	if err := c.kube.Patch(ctx, patched, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+		// The operation has run, so its connection details are still
+		// published while the removal is retried
+		c.recorder.Event(cr, event.Warning(reasonOperation, errors.Wrap(err, "failed to remove operation annotation")))
+		return conn, nil
+	}
+	cr.SetAnnotations(patched.GetAnnotations())
+	cr.SetResourceVersion(patched.GetResourceVersion())
+	cr.Status.AtProvider.LastOperation.AnnotationRemoved = true
+
+	return conn, nil
+}
+
+func (c *external) runOperation(ctx context.Context, operation apisv1alpha1.ServerOperation, server *hcloudsdk.Server) (managed.ConnectionDetails, error) {
+	var action *hcloudsdk.Action
+	var conn managed.ConnectionDetails
+	var err error
+
+	switch operation {
+	case apisv1alpha1.ServerOperationReboot:
+		action, _, err = c.hcloud.Server.Reboot(ctx, server)
+	case apisv1alpha1.ServerOperationReset:
+		action, _, err = c.hcloud.Server.Reset(ctx, server)
+	case apisv1alpha1.ServerOperationResetPassword:
+		var result hcloudsdk.ServerResetPasswordResult
+		result, _, err = c.hcloud.Server.ResetPassword(ctx, server)
+		action = result.Action
+		conn = managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte(result.RootPassword)}
+	default:
+		return nil, fmt.Errorf("unknown operation %q, must be one of %v", operation, apisv1alpha1.ServerOperations)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s server", operationVerb(operation))
+	}
+
+	// A new root password is set as soon as the request succeeds, so it's
+	// returned even if the action then fails
+	if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
+		return conn, errors.Wrapf(err, "error waiting for server to %s", operationVerb(operation))
+	}
+
+	return conn, nil
+}
+
+func operationVerb(operation apisv1alpha1.ServerOperation) string {
+	switch operation {
+	case apisv1alpha1.ServerOperationReboot:
+		return "reboot"
+	case apisv1alpha1.ServerOperationReset:
+		return "reset"
+	default:
+		return "reset the password of"
+	}
+}
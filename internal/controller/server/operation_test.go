@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+func TestOperate(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	seeded := api.AddServer(schema.Server{Name: "example"})
+
+	errBoom := errors.New("boom")
+
+	type want struct {
+		conn    managed.ConnectionDetails
+		result  apisv1alpha1.OperationResult
+		writes  []string
+		patched bool
+		ran     bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason       string
+		annotation   *string
+		last         *apisv1alpha1.OperationStatus
+		failActions  bool
+		statusUpdate error
+		patch        error
+		want         want
+	}{
+		"NoOperation": {
+			reason: "Nothing should happen without the annotation",
+		},
+		"Reboot": {
+			reason:     "A requested reboot should be run once and recorded",
+			annotation: hcloudsdk.Ptr("Reboot"),
+			want: want{
+				result:  apisv1alpha1.OperationSucceeded,
+				writes:  []string{"status", "patch"},
+				patched: true,
+				ran:     true,
+			},
+		},
+		"RequestedAgain": {
+			reason:     "The same operation should be run again once the previous request's annotation was removed",
+			annotation: hcloudsdk.Ptr("Reboot"),
+			last:       &apisv1alpha1.OperationStatus{Operation: apisv1alpha1.ServerOperationReboot, Result: apisv1alpha1.OperationSucceeded, AnnotationRemoved: true},
+			want: want{
+				result:  apisv1alpha1.OperationSucceeded,
+				writes:  []string{"status", "patch"},
+				patched: true,
+				ran:     true,
+			},
+		},
+		"AlreadyRun": {
+			reason:     "An operation whose annotation couldn't be removed should not be run again",
+			annotation: hcloudsdk.Ptr("Reboot"),
+			last:       &apisv1alpha1.OperationStatus{Operation: apisv1alpha1.ServerOperationReboot, Result: apisv1alpha1.OperationSucceeded},
+			want: want{
+				result:  apisv1alpha1.OperationSucceeded,
+				writes:  []string{"patch"},
+				patched: true,
+			},
+		},
+		"PatchFailed": {
+			reason:     "A new root password should still be published when the annotation can't be removed",
+			annotation: hcloudsdk.Ptr("ResetPassword"),
+			patch:      errBoom,
+			want: want{
+				conn:   managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte("fake-root-password")},
+				result: apisv1alpha1.OperationSucceeded,
+				writes: []string{"status", "patch"},
+				ran:    true,
+			},
+		},
+		"ResetPasswordWaitFailed": {
+			reason:      "A new root password should be published even if the action then fails",
+			annotation:  hcloudsdk.Ptr("ResetPassword"),
+			failActions: true,
+			want: want{
+				conn:    managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte("fake-root-password")},
+				result:  apisv1alpha1.OperationFailed,
+				writes:  []string{"status", "patch"},
+				patched: true,
+				ran:     true,
+			},
+		},
+		"ResetPassword": {
+			reason:     "A new root password should be published",
+			annotation: hcloudsdk.Ptr("ResetPassword"),
+			want: want{
+				conn:    managed.ConnectionDetails{xpv1.ResourceCredentialsSecretPasswordKey: []byte("fake-root-password")},
+				result:  apisv1alpha1.OperationSucceeded,
+				writes:  []string{"status", "patch"},
+				patched: true,
+				ran:     true,
+			},
+		},
+		"Unknown": {
+			reason:     "An unknown operation should be recorded as failed rather than retried",
+			annotation: hcloudsdk.Ptr("Rebuild"),
+			want: want{
+				result:  apisv1alpha1.OperationFailed,
+				writes:  []string{"status", "patch"},
+				patched: true,
+			},
+		},
+		"StatusFailed": {
+			reason:       "The annotation should be kept when the outcome cannot be saved, so that it is not lost",
+			annotation:   hcloudsdk.Ptr("Reboot"),
+			statusUpdate: errBoom,
+			want: want{
+				result: apisv1alpha1.OperationSucceeded,
+				writes: []string{"status"},
+				ran:    true,
+				err:    errors.Wrap(errBoom, "failed to save operation status"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var writes []string
+			kube := &test.MockClient{
+				MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+					writes = append(writes, "status")
+					return tc.statusUpdate
+				},
+				MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					writes = append(writes, "patch")
+					if tc.patch != nil {
+						return tc.patch
+					}
+					obj.SetAnnotations(nil)
+					obj.SetResourceVersion("2")
+					return nil
+				},
+			}
+
+			cr := server(seeded.ID, true, true)
+			if tc.annotation != nil {
+				cr.SetAnnotations(map[string]string{apisv1alpha1.AnnotationKeyOperation: *tc.annotation})
+			}
+			cr.Status.AtProvider.LastOperation = tc.last
+
+			if tc.failActions {
+				api.FailActions("action_failed", "guest agent not running")
+				defer api.FailActions("", "")
+			}
+
+			e := external{kube: kube, hcloud: api.Client(), recorder: event.NewNopRecorder()}
+			s := fake.GetServer(t, e.hcloud, seeded.ID)
+			requests := len(api.Requests())
+
+			conn, err := e.operate(context.Background(), cr, s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.operate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conn, conn); diff != "" {
+				t.Errorf("\n%s\ne.operate(...): -want connection details, +got connection details:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.writes, writes); diff != "" {
+				t.Errorf("\n%s\ne.operate(...): -want writes, +got writes:\n%s\n", tc.reason, diff)
+			}
+			if tc.annotation != nil && operationRequested(cr) == tc.want.patched {
+				t.Errorf("\n%s\ne.operate(...): want annotation removed %t, got annotation %t", tc.reason, tc.want.patched, operationRequested(cr))
+			}
+
+			if ran := ranOperation(api.Requests()[requests:]); ran != tc.want.ran {
+				t.Errorf("\n%s\ne.operate(...): want operation run %t, got %t", tc.reason, tc.want.ran, ran)
+			}
+
+			var result apisv1alpha1.OperationResult
+			if op := cr.Status.AtProvider.LastOperation; op != nil {
+				result = op.Result
+				if op.AnnotationRemoved != tc.want.patched {
+					t.Errorf("\n%s\ne.operate(...): want annotation removal recorded %t, got %t", tc.reason, tc.want.patched, op.AnnotationRemoved)
+				}
+			}
+			if result != tc.want.result {
+				t.Errorf("\n%s\ne.operate(...): want result %q, got %q", tc.reason, tc.want.result, result)
+			}
+		})
+	}
+}
+
+// ranOperation reports whether any of the requests asked for a server action
+func ranOperation(requests []string) bool {
+	for _, r := range requests {
+		if strings.HasPrefix(r, http.MethodPost+" ") && strings.Contains(r, "/actions/") {
+			return true
+		}
+	}
+	return false
+}
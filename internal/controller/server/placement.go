@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+// placementGroupUpToDate reports whether the server is in the placement
+// group wanted, if any
+func placementGroupUpToDate(want *int64, live *hcloudsdk.PlacementGroup) bool {
+	if want == nil || live == nil {
+		return want == nil && live == nil
+	}
+	return *want == live.ID
+}
+
+// updatePlacementGroup moves the server into the placement group wanted, or
+// out of its placement group when none is wanted. Hetzner only adds stopped
+// servers to a placement group, so the server is powered off first and left
+// off for the caller to power on again. It returns false while waiting for a
+// graceful shutdown.
+func (c *external) updatePlacementGroup(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server, now time.Time) (bool, error) {
+	want := cr.Spec.ForProvider.PlacementGroupID
+
+	if want != nil {
+		// Check the placement group exists before changing anything
+		placementGroup, _, err := c.hcloud.PlacementGroup.GetByID(ctx, *want)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to query placement group")
+		}
+		if placementGroup == nil {
+			return false, fmt.Errorf("unknown placement group %d", *want)
+		}
+
+		if server.Status != hcloudsdk.ServerStatusOff {
+			stopped, err := c.setPower(ctx, cr, server, false, now)
+			if err != nil || !stopped {
+				return false, err
+			}
+		}
+	}
+
+	if server.PlacementGroup != nil {
+		action, _, err := c.hcloud.Server.RemoveFromPlacementGroup(ctx, server)
+		if err == nil {
+			err = c.waitForActions(ctx, action)
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to remove server from placement group %d", server.PlacementGroup.ID)
+		}
+	}
+
+	if want == nil {
+		return true, nil
+	}
+
+	action, _, err := c.hcloud.Server.AddToPlacementGroup(ctx, server, &hcloudsdk.PlacementGroup{ID: *want})
+	if err == nil {
+		err = c.waitForActions(ctx, action)
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to add server to placement group %d", *want)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+func TestUpdatePlacementGroup(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	groupA := api.AddPlacementGroup(schema.PlacementGroup{Name: "a", Type: string(hcloudsdk.PlacementGroupTypeSpread)})
+	groupB := api.AddPlacementGroup(schema.PlacementGroup{Name: "b", Type: string(hcloudsdk.PlacementGroupTypeSpread)})
+
+	type want struct {
+		moved          bool
+		placementGroup *int64
+		status         hcloudsdk.ServerStatus
+		err            error
+	}
+
+	cases := map[string]struct {
+		reason         string
+		status         hcloudsdk.ServerStatus
+		placementGroup *schema.PlacementGroup
+		policy         *apisv1alpha1.PowerOffPolicy
+		want           *int64
+		expect         want
+	}{
+		"Add": {
+			reason: "A running server should be stopped and added to the placement group",
+			status: hcloudsdk.ServerStatusRunning,
+			want:   &groupA.ID,
+			expect: want{
+				moved:          true,
+				placementGroup: &groupA.ID,
+				status:         hcloudsdk.ServerStatusOff,
+			},
+		},
+		"Move": {
+			reason:         "A server should be moved from one placement group to another",
+			status:         hcloudsdk.ServerStatusOff,
+			placementGroup: &groupA,
+			want:           &groupB.ID,
+			expect: want{
+				moved:          true,
+				placementGroup: &groupB.ID,
+				status:         hcloudsdk.ServerStatusOff,
+			},
+		},
+		"Remove": {
+			reason:         "A server should be removed from its placement group without being stopped",
+			status:         hcloudsdk.ServerStatusRunning,
+			placementGroup: &groupA,
+			expect: want{
+				moved:  true,
+				status: hcloudsdk.ServerStatusRunning,
+			},
+		},
+		"ShuttingDown": {
+			reason: "A server with a Shutdown policy should not be added until it has shut down",
+			status: hcloudsdk.ServerStatusRunning,
+			policy: &apisv1alpha1.PowerOffPolicy{Method: apisv1alpha1.PowerOffMethodShutdown},
+			want:   &groupA.ID,
+			expect: want{
+				moved:  false,
+				status: hcloudsdk.ServerStatusOff,
+			},
+		},
+		"Unknown": {
+			reason: "A server should not be stopped for a placement group which does not exist",
+			status: hcloudsdk.ServerStatusRunning,
+			want:   hcloudsdk.Ptr[int64](999),
+			expect: want{
+				status: hcloudsdk.ServerStatusRunning,
+				err:    errors.New("unknown placement group 999"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			seeded := api.AddServer(schema.Server{Name: name, Status: string(tc.status), PlacementGroup: tc.placementGroup})
+
+			cr := server(seeded.ID, true, true)
+			cr.Spec.ForProvider.PlacementGroupID = tc.want
+			cr.Spec.ForProvider.PowerOff = tc.policy
+
+			e := external{hcloud: api.Client()}
+			s := fake.GetServer(t, e.hcloud, seeded.ID)
+
+			moved, err := e.updatePlacementGroup(context.Background(), cr, s, time.Now())
+			if diff := cmp.Diff(tc.expect.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.updatePlacementGroup(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if moved != tc.expect.moved {
+				t.Errorf("\n%s\ne.updatePlacementGroup(...): want moved %t, got %t", tc.reason, tc.expect.moved, moved)
+			}
+
+			got, _ := api.Server(seeded.ID)
+			var placementGroup *int64
+			if got.PlacementGroup != nil {
+				placementGroup = &got.PlacementGroup.ID
+			}
+			if diff := cmp.Diff(tc.expect.placementGroup, placementGroup); diff != "" {
+				t.Errorf("\n%s\ne.updatePlacementGroup(...): -want placement group, +got placement group:\n%s\n", tc.reason, diff)
+			}
+			if hcloudsdk.ServerStatus(got.Status) != tc.expect.status {
+				t.Errorf("\n%s\ne.updatePlacementGroup(...): want server %s, got %s", tc.reason, tc.expect.status, got.Status)
+			}
+		})
+	}
+}
+
+func TestUpdateMovesRunningServer(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	group := api.AddPlacementGroup(schema.PlacementGroup{Name: "group", Type: string(hcloudsdk.PlacementGroupTypeSpread)})
+	seeded := api.AddServer(schema.Server{Name: "example"})
+
+	cr := server(seeded.ID, true, true)
+	cr.Spec.ForProvider.PlacementGroupID = &group.ID
+
+	e := external{kube: test.NewMockClient(), hcloud: api.Client()}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+
+	got, _ := api.Server(seeded.ID)
+	if got.PlacementGroup == nil || got.PlacementGroup.ID != group.ID {
+		t.Errorf("e.Update(...): want the server in placement group %d, got %v", group.ID, got.PlacementGroup)
+	}
+	if hcloudsdk.ServerStatus(got.Status) != hcloudsdk.ServerStatusRunning {
+		t.Errorf("e.Update(...): want the server running again after the move, got %s", got.Status)
+	}
+	if !cr.Status.AtProvider.PowerOn {
+		t.Error("e.Update(...): want the server recorded as powered on")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// defaultShutdownTimeout is used when a Shutdown power off policy has no
+// timeout set
+const defaultShutdownTimeout = 5 * time.Minute
+
+// powerState returns whether the server should be powered on at the given
+// time and, for scheduled servers, when that next changes.
+func powerState(params v1alpha1.ServerParameters, now time.Time) (bool, *metav1.Time, error) {
+	if params.PowerSchedule == nil {
+		return params.PowerOn, nil, nil
+	}
+
+	powerOn, next, err := params.PowerSchedule.State(now, params.PowerOn)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return powerOn, &metav1.Time{Time: next}, nil
+}
+
+// setPower powers the server on or off, returning whether it is now in that
+// state. A graceful shutdown is not waited for here, as it can take longer
+// than a reconcile is allowed. It is checked again on each update until the
+// server stops or the timeout passes and the power is cut.
+func (c *external) setPower(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server, powerOn bool, now time.Time) (bool, error) {
+	var action *hcloudsdk.Action
+	var err error
+
+	switch policy := cr.Spec.ForProvider.PowerOff; {
+	case powerOn:
+		cr.Status.AtProvider.ShutdownRequestedAt = nil
+		action, _, err = c.hcloud.Server.Poweron(ctx, server)
+
+	case server.Status == hcloudsdk.ServerStatusOff:
+		// Already stopped, such as by a shutdown
+		cr.Status.AtProvider.ShutdownRequestedAt = nil
+		return true, nil
+
+	case policy != nil && policy.Method == apisv1alpha1.PowerOffMethodShutdown && cr.Status.AtProvider.ShutdownRequestedAt == nil:
+		action, _, err = c.hcloud.Server.Shutdown(ctx, server)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to shut down server")
+		}
+		if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
+			return false, errors.Wrap(err, "error waiting for server to shut down")
+		}
+		cr.Status.AtProvider.ShutdownRequestedAt = &metav1.Time{Time: now}
+		return false, nil
+
+	case policy != nil && policy.Method == apisv1alpha1.PowerOffMethodShutdown && now.Before(cr.Status.AtProvider.ShutdownRequestedAt.Add(shutdownTimeout(policy))):
+		// Still shutting down
+		return false, nil
+
+	default:
+		cr.Status.AtProvider.ShutdownRequestedAt = nil
+		action, _, err = c.hcloud.Server.Poweroff(ctx, server)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to change power state")
+	}
+
+	if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
+		return false, errors.Wrap(err, "error waiting for server to change power state")
+	}
+
+	return true, nil
+}
+
+func shutdownTimeout(policy *apisv1alpha1.PowerOffPolicy) time.Duration {
+	if policy.ShutdownTimeoutSeconds <= 0 {
+		return defaultShutdownTimeout
+	}
+	return time.Duration(policy.ShutdownTimeoutSeconds) * time.Second
+}
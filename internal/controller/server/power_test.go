@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+func TestPowerState(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// On during working hours in Berlin
+	workingHours := &apisv1alpha1.PowerSchedule{
+		On:       "0 8 * * 1-5",
+		Off:      "0 20 * * 1-5",
+		TimeZone: "Europe/Berlin",
+	}
+
+	type want struct {
+		powerOn bool
+		next    *metav1.Time
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.ServerParameters
+		now    time.Time
+		want   want
+	}{
+		"NoSchedule": {
+			reason: "A server without a schedule should follow powerOn",
+			params: v1alpha1.ServerParameters{PowerOn: true},
+			now:    time.Date(2024, time.July, 6, 12, 0, 0, 0, berlin),
+			want: want{
+				powerOn: true,
+			},
+		},
+		"WorkingDay": {
+			reason: "A server should be on during the scheduled window, until the off time",
+			params: v1alpha1.ServerParameters{PowerSchedule: workingHours},
+			now:    time.Date(2024, time.July, 3, 12, 0, 0, 0, berlin),
+			want: want{
+				powerOn: true,
+				next:    &metav1.Time{Time: time.Date(2024, time.July, 3, 20, 0, 0, 0, berlin)},
+			},
+		},
+		"AtOnTime": {
+			reason: "A server should be on from the moment it is scheduled on",
+			params: v1alpha1.ServerParameters{PowerSchedule: workingHours},
+			now:    time.Date(2024, time.July, 3, 8, 0, 0, 0, berlin),
+			want: want{
+				powerOn: true,
+				next:    &metav1.Time{Time: time.Date(2024, time.July, 3, 20, 0, 0, 0, berlin)},
+			},
+		},
+		"Night": {
+			reason: "A server should be off overnight, until the next morning",
+			params: v1alpha1.ServerParameters{PowerOn: true, PowerSchedule: workingHours},
+			now:    time.Date(2024, time.July, 3, 23, 0, 0, 0, berlin),
+			want: want{
+				powerOn: false,
+				next:    &metav1.Time{Time: time.Date(2024, time.July, 4, 8, 0, 0, 0, berlin)},
+			},
+		},
+		"Weekend": {
+			reason: "A server should stay off over the weekend, until Monday morning",
+			params: v1alpha1.ServerParameters{PowerOn: true, PowerSchedule: workingHours},
+			now:    time.Date(2024, time.July, 7, 12, 0, 0, 0, berlin),
+			want: want{
+				powerOn: false,
+				next:    &metav1.Time{Time: time.Date(2024, time.July, 8, 8, 0, 0, 0, berlin)},
+			},
+		},
+		"TimeZone": {
+			reason: "A schedule should be evaluated in its time zone",
+			params: v1alpha1.ServerParameters{PowerSchedule: workingHours},
+			now:    time.Date(2024, time.July, 3, 6, 30, 0, 0, time.UTC),
+			want: want{
+				powerOn: true,
+				next:    &metav1.Time{Time: time.Date(2024, time.July, 3, 20, 0, 0, 0, berlin)},
+			},
+		},
+		"NeverFired": {
+			reason: "A server should follow powerOn until its schedule first fires",
+			params: v1alpha1.ServerParameters{
+				PowerOn:       true,
+				PowerSchedule: &apisv1alpha1.PowerSchedule{On: "0 8 29 2 *", Off: "0 20 29 2 *"},
+			},
+			now: time.Date(2025, time.July, 3, 12, 0, 0, 0, time.UTC),
+			want: want{
+				powerOn: true,
+				next:    &metav1.Time{Time: time.Date(2028, time.February, 29, 20, 0, 0, 0, time.UTC)},
+			},
+		},
+		"InvalidSchedule": {
+			reason: "An invalid cron expression should return an error",
+			params: v1alpha1.ServerParameters{PowerSchedule: &apisv1alpha1.PowerSchedule{On: "every morning", Off: "0 20 * * *"}},
+			now:    time.Date(2024, time.July, 3, 12, 0, 0, 0, time.UTC),
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			powerOn, next, err := powerState(tc.params, tc.now)
+			if tc.want.err != (err != nil) {
+				t.Fatalf("\n%s\npowerState(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if powerOn != tc.want.powerOn {
+				t.Errorf("\n%s\npowerState(...): want power on %t, got %t", tc.reason, tc.want.powerOn, powerOn)
+			}
+			if diff := cmp.Diff(tc.want.next, next, cmp.Comparer(func(a, b metav1.Time) bool { return a.Equal(&b) })); diff != "" {
+				t.Errorf("\n%s\npowerState(...): -want next transition, +got next transition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetPower(t *testing.T) {
+	now := time.Now()
+	shutdown := &apisv1alpha1.PowerOffPolicy{Method: apisv1alpha1.PowerOffMethodShutdown, ShutdownTimeoutSeconds: 300}
+
+	type want struct {
+		done              bool
+		status            hcloudsdk.ServerStatus
+		shutdownRequested bool
+	}
+
+	cases := map[string]struct {
+		reason            string
+		status            hcloudsdk.ServerStatus
+		policy            *apisv1alpha1.PowerOffPolicy
+		shutdownRequested *metav1.Time
+		powerOn           bool
+		want              want
+	}{
+		"PowerOn": {
+			reason: "A stopped server should be powered on",
+			status: hcloudsdk.ServerStatusOff,
+			policy: shutdown,
+			// A shutdown is no longer waited for once the server is wanted on
+			shutdownRequested: &metav1.Time{Time: now},
+			powerOn:           true,
+			want: want{
+				done:   true,
+				status: hcloudsdk.ServerStatusRunning,
+			},
+		},
+		"PowerOff": {
+			reason: "A server without a policy should have its power cut",
+			status: hcloudsdk.ServerStatusRunning,
+			want: want{
+				done:   true,
+				status: hcloudsdk.ServerStatusOff,
+			},
+		},
+		"Shutdown": {
+			reason: "A server with a Shutdown policy should be asked to shut down and then waited for",
+			status: hcloudsdk.ServerStatusRunning,
+			policy: shutdown,
+			want: want{
+				done:              false,
+				status:            hcloudsdk.ServerStatusOff,
+				shutdownRequested: true,
+			},
+		},
+		"ShuttingDown": {
+			reason:            "A server still shutting down within the timeout should be left alone",
+			status:            hcloudsdk.ServerStatusRunning,
+			policy:            shutdown,
+			shutdownRequested: &metav1.Time{Time: now.Add(-time.Minute)},
+			want: want{
+				done:              false,
+				status:            hcloudsdk.ServerStatusRunning,
+				shutdownRequested: true,
+			},
+		},
+		"ShutDown": {
+			reason:            "A server which has shut down should be reported as off",
+			status:            hcloudsdk.ServerStatusOff,
+			policy:            shutdown,
+			shutdownRequested: &metav1.Time{Time: now.Add(-time.Minute)},
+			want: want{
+				done:   true,
+				status: hcloudsdk.ServerStatusOff,
+			},
+		},
+		"ShutdownTimedOut": {
+			reason:            "A server which has not shut down within the timeout should have its power cut",
+			status:            hcloudsdk.ServerStatusRunning,
+			policy:            shutdown,
+			shutdownRequested: &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			want: want{
+				done:   true,
+				status: hcloudsdk.ServerStatusOff,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := fake.NewAPI()
+			defer api.Close()
+
+			seeded := api.AddServer(schema.Server{Name: "example", Status: string(tc.status)})
+
+			cr := server(seeded.ID, tc.powerOn, !tc.powerOn)
+			cr.Spec.ForProvider.PowerOff = tc.policy
+			cr.Status.AtProvider.ShutdownRequestedAt = tc.shutdownRequested
+
+			e := external{hcloud: api.Client()}
+			s := fake.GetServer(t, e.hcloud, seeded.ID)
+
+			done, err := e.setPower(context.Background(), cr, s, tc.powerOn, now)
+			if err != nil {
+				t.Fatalf("\n%s\ne.setPower(...): %s", tc.reason, err)
+			}
+			if done != tc.want.done {
+				t.Errorf("\n%s\ne.setPower(...): want done %t, got %t", tc.reason, tc.want.done, done)
+			}
+			if got, _ := api.Server(seeded.ID); hcloudsdk.ServerStatus(got.Status) != tc.want.status {
+				t.Errorf("\n%s\ne.setPower(...): want server %s, got %s", tc.reason, tc.want.status, got.Status)
+			}
+			if got := cr.Status.AtProvider.ShutdownRequestedAt != nil; got != tc.want.shutdownRequested {
+				t.Errorf("\n%s\ne.setPower(...): want shutdown requested %t, got %t", tc.reason, tc.want.shutdownRequested, got)
+			}
+		})
+	}
+}
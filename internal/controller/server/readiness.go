@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+const (
+	defaultProbeTimeout = 600 * time.Second
+
+	// Each attempt is kept short as it runs within Observe. A failed
+	// attempt is retried on the next poll.
+	probeAttemptTimeout = 2 * time.Second
+
+	// SSH servers may send other lines before their banner
+	maxBannerLines = 10
+)
+
+// readiness returns the Ready condition of a server which Hetzner reports as
+// running or off
+func readiness(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server) xpv1.Condition {
+	p := cr.Spec.ForProvider.ReadinessProbe
+	if p == nil {
+		return xpv1.Available()
+	}
+
+	// Readiness is latched: the probe is not repeated once it passes, so
+	// that the server stays ready when it is stopped or restarted
+	if cr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+		return xpv1.Available()
+	}
+
+	if server.Status == hcloudsdk.ServerStatusRunning && probe(ctx, p, server) {
+		return xpv1.Available()
+	}
+
+	timeout := defaultProbeTimeout
+	if p.TimeoutSeconds > 0 {
+		timeout = time.Duration(p.TimeoutSeconds) * time.Second
+	}
+	if time.Since(server.Created) > timeout {
+		return xpv1.Unavailable().WithMessage(fmt.Sprintf("%s readiness probe did not pass within %s of the server being created", p.Type, timeout))
+	}
+
+	return xpv1.Creating().WithMessage(fmt.Sprintf("waiting for the %s readiness probe to pass", p.Type))
+}
+
+// probe reports whether the server passes the readiness probe
+func probe(ctx context.Context, p *apisv1alpha1.ReadinessProbe, server *hcloudsdk.Server) bool {
+	if p.Type == apisv1alpha1.ReadinessProbeLabel {
+		if p.Label == nil {
+			return false
+		}
+		value, ok := server.Labels[p.Label.Key]
+		return ok && (p.Label.Value == nil || *p.Label.Value == value)
+	}
+
+	addresses := serverAddresses(server)
+	if len(addresses) == 0 {
+		return false
+	}
+
+	port := p.Port
+	if port == 0 {
+		port = sshPort
+	}
+
+	// The dial and banner read share one deadline
+	ctx, cancel := context.WithTimeout(ctx, probeAttemptTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addresses[0], strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	defer conn.Close() //nolint:errcheck
+
+	if p.Type == apisv1alpha1.ReadinessProbeTCP {
+		return true
+	}
+
+	// SSH servers send their banner as soon as a client connects
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return false
+	}
+	r := bufio.NewReader(conn)
+	for i := 0; i < maxBannerLines; i++ {
+		line, err := r.ReadString('\n')
+		if strings.HasPrefix(line, "SSH-") {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+	}
+
+	return false
+}
+
+// probeLabels returns the live label a Label probe checks for, unless it is
+// also one of the server's desired labels. The server sets it on itself, so
+// the provider keeps it rather than treating it as drift.
+func probeLabels(p v1alpha1.ServerParameters, live map[string]string) map[string]string {
+	if p.ReadinessProbe == nil || p.ReadinessProbe.Type != apisv1alpha1.ReadinessProbeLabel || p.ReadinessProbe.Label == nil {
+		return nil
+	}
+
+	key := p.ReadinessProbe.Label.Key
+	if _, ok := p.Labels[key]; ok {
+		return nil
+	}
+	value, ok := live[key]
+	if !ok {
+		return nil
+	}
+
+	return map[string]string{key: value}
+}
@@ -19,8 +19,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +40,7 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -48,23 +51,14 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
-)
-
-func getConnectionDetails(server hcloudsdk.ServerCreateResult) managed.ConnectionDetails {
-	conn := managed.ConnectionDetails{
-		xpv1.ResourceCredentialsSecretEndpointKey: []byte(server.Server.PublicNet.IPv4.IP.String()),
-		xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
-		xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
-	}
-	if password := server.RootPassword; password != "" {
-		conn[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(password)
-	}
 
-	return conn
-}
+	reasonReplaced  event.Reason = "ReplacedExternalResource"
+	reasonOperation event.Reason = "ServerOperation"
+)
 
 // Setup adds a controller that reconciles Server managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// The options are given to every Hetzner client it creates.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	name := managed.ControllerName(v1alpha1.ServerGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -78,9 +72,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newServiceFn: hcloud.NewClient,
+			clientOpts:   opts,
+			recorder:     event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithConnectionPublishers(cps...))
 
@@ -97,7 +94,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds string) (*hcloud.Client, error)
+	newServiceFn func(creds string, opts ...hcloud.Option) (*hcloud.Client, error)
+	clientOpts   []hcloud.Option
+	recorder     event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -120,28 +119,40 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
+	cd, err := pc.GetCredentials(cr.Spec.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(string(data))
+	svc, err := c.newServiceFn(string(data), append([]hcloud.Option{
+		hcloud.WithDefaultLabels(pc.Spec.Labels.DefaultLabels(mg)),
+		hcloud.WithLabelMode(pc.Spec.Labels.GetMode()),
+		hcloud.WithProviderConfig(pc.Name, cr.Spec.Project),
+	}, c.clientOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{
-		kube:   c.kube,
-		hcloud: svc,
-	}, nil
+	return tracing.NewExternalClient(v1alpha1.ServerKind, func(mg resource.Managed) int64 {
+		return mg.(*v1alpha1.Server).Status.AtProvider.ID
+	}, &external{
+		kube:     c.kube,
+		hcloud:   svc,
+		recorder: c.recorder,
+	}), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube   client.Client
-	hcloud *hcloud.Client
+	kube     client.Client
+	hcloud   *hcloud.Client
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -150,7 +161,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotServer)
 	}
 
-	server, _, err := c.hcloud.Client.Server.GetByID(ctx, cr.Status.AtProvider.ID)
+	server, _, err := c.hcloud.Server.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
@@ -158,16 +169,55 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
+	if cr.NeedsReplacement() {
+		// The server is replaced by Update, so that it is never deleted
+		// just by being observed
+		cr.SetConditions(apisv1alpha1.ReplacementPending())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+	}
+	if cr.GetCondition(apisv1alpha1.TypeReplacementPending).Status == corev1.ConditionTrue {
+		cr.SetConditions(apisv1alpha1.ReplacementNotPending())
+	}
+
+	powerOn, nextPowerTransition, err := powerState(cr.Spec.ForProvider, time.Now())
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.AtProvider.Status = server.Status
+	cr.Status.AtProvider.NextPowerTransition = nextPowerTransition
+	cr.Status.AtProvider.PublicIPv4 = ""
+	cr.Status.AtProvider.PublicIPv6 = ""
+	if ip := server.PublicNet.IPv4.IP; ip != nil {
+		cr.Status.AtProvider.PublicIPv4 = ip.String()
+	}
+	if network := server.PublicNet.IPv6.Network; network != nil {
+		cr.Status.AtProvider.PublicIPv6 = network.String()
+	}
+	cr.Status.AtProvider.AttachedISO = ""
+	if server.ISO != nil {
+		cr.Status.AtProvider.AttachedISO = server.ISO.Name
+	}
+
 	if server.Status == hcloudsdk.ServerStatusRunning || server.Status == hcloudsdk.ServerStatusOff {
-		// Running or off
-		cr.SetConditions(xpv1.Available())
+		// Running or off, and passing any readiness probe
+		cr.SetConditions(readiness(ctx, cr, server))
 	} else {
 		cr.SetConditions(xpv1.Creating())
 	}
 
+	upToDate := cr.IsUpToDate() &&
+		cr.Status.AtProvider.PowerOn == powerOn &&
+		isoUpToDate(cr.Spec.ForProvider.ISO, server.ISO) &&
+		diffMemberships(cr, server).empty() &&
+		placementGroupUpToDate(cr.Spec.ForProvider.PlacementGroupID, server.PlacementGroup) &&
+		!operationRequested(cr) &&
+		c.hcloud.LabelsUpToDate(server.Labels, cr.Spec.ForProvider.Labels.Map(), probeLabels(cr.Spec.ForProvider, server.Labels))
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: cr.IsUpToDate(),
+		ResourceExists:    true,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: connectionDetails(server, parseHostKey(cr.Status.AtProvider.HostKey)),
 	}, nil
 }
 
@@ -190,7 +240,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	// Find image
-	image, _, err := c.hcloud.Client.Image.GetByNameAndArchitecture(ctx, cr.Spec.ForProvider.Image, cr.Spec.ForProvider.Architecture)
+	image, _, err := c.hcloud.Image.GetByNameAndArchitecture(ctx, cr.Spec.ForProvider.Image, cr.Spec.ForProvider.Architecture)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to get image")
 	}
@@ -207,7 +257,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	// Find placement group
 	var placementGroup *hcloudsdk.PlacementGroup
 	if placementGroupId := cr.Spec.ForProvider.PlacementGroupID; placementGroupId != nil {
-		group, _, err := c.hcloud.Client.PlacementGroup.GetByID(ctx, *placementGroupId)
+		group, _, err := c.hcloud.PlacementGroup.GetByID(ctx, *placementGroupId)
 		if err != nil {
 			return managed.ExternalCreation{}, errors.Wrap(err, "failed to query placement group")
 		}
@@ -218,7 +268,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	// Find serverType
-	serverType, _, err := c.hcloud.Client.ServerType.GetByName(ctx, cr.Spec.ForProvider.ServerType)
+	serverType, _, err := c.hcloud.ServerType.GetByName(ctx, cr.Spec.ForProvider.ServerType)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to get server type")
 	}
@@ -232,21 +282,63 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, err
 	}
 
+	// Generate a host key that is installed by cloud-init
+	var hostKey string
+	var extraUserData []userDataPart
+	if cr.Spec.ForProvider.GenerateHostKey {
+		public, private, err := generateSSHKey(cr.GetName())
+		if err != nil {
+			return managed.ExternalCreation{}, err
+		}
+
+		hostKey = public
+		extraUserData = append(extraUserData, hostKeyUserData(public, private))
+	}
+
+	// Resolve user data
+	userData, err := c.userData(ctx, cr, extraUserData...)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	// Ensure SSH keys
 	sshKeys, err := c.hcloud.UpsertSSHKeys(ctx, cr.Spec.ForProvider.SSHKeys...)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to upsert ssh key")
 	}
 
+	// Generate a key pair that only this server uses
+	var generatedKey *hcloudsdk.SSHKey
+	var privateKey, publicKey []byte
+	if cr.Spec.ForProvider.GenerateSSHKey {
+		public, private, err := generateSSHKey(cr.GetName())
+		if err != nil {
+			return managed.ExternalCreation{}, err
+		}
+
+		generatedKey, err = c.hcloud.UpsertSSHKey(ctx, public)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, "failed to upload generated ssh key")
+		}
+
+		sshKeys = append(sshKeys, generatedKey)
+		privateKey, publicKey = private, []byte(public)
+	}
+
 	cr.Status.SetConditions(xpv1.Creating())
 
-	server, _, err := c.hcloud.Client.Server.Create(ctx, hcloudsdk.ServerCreateOpts{
+	labels, err := c.hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels.Map())
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	server, _, err := c.hcloud.Server.Create(ctx, hcloudsdk.ServerCreateOpts{
 		Automount:      &cr.Spec.ForProvider.AutoMount,
 		Name:           cr.ObjectMeta.Name,
 		Datacenter:     datacenter,
 		Firewalls:      firewalls,
 		Image:          image,
-		Labels:         hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels),
+		Labels:         labels,
 		Location:       location,
 		Networks:       networks,
 		PlacementGroup: placementGroup,
@@ -257,21 +349,40 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		ServerType:       serverType,
 		SSHKeys:          sshKeys,
 		StartAfterCreate: &cr.Spec.ForProvider.StartAfterCreate,
-		UserData:         cr.Spec.ForProvider.UserData,
+		UserData:         userData,
 		Volumes:          volumes,
 	})
 	if err != nil {
+		if generatedKey != nil {
+			// Nothing else can use the key, so don't leave it behind
+			if err := c.deleteSSHKey(ctx, generatedKey.ID); err != nil {
+				return managed.ExternalCreation{}, err
+			}
+		}
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to create server")
 	}
 
 	cr.Status.AtProvider.ID = server.Server.ID
+	if generatedKey != nil {
+		cr.Status.AtProvider.GeneratedSSHKeyID = generatedKey.ID
+	}
+	cr.Status.AtProvider.HostKey = hostKey
 	cr.Status.AtProvider.ServerParameters = &cr.Spec.ForProvider
 	if err := c.kube.Status().Update(ctx, cr); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "failed to save create status")
 	}
 
+	conn := connectionDetails(server.Server, parseHostKey(hostKey))
+	if password := server.RootPassword; password != "" {
+		conn[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(password)
+	}
+	if privateKey != nil {
+		conn[connectionKeyPrivateKey] = privateKey
+		conn[connectionKeyPublicKey] = publicKey
+	}
+
 	return managed.ExternalCreation{
-		ConnectionDetails: getConnectionDetails(server),
+		ConnectionDetails: conn,
 	}, nil
 }
 
@@ -281,44 +392,90 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotServer)
 	}
 
-	server, _, err := c.hcloud.Client.Server.GetByID(ctx, cr.Status.AtProvider.ID)
+	server, _, err := c.hcloud.Server.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to get server")
 	}
+	if server == nil {
+		return managed.ExternalUpdate{}, fmt.Errorf("unknown server")
+	}
+
+	if cr.NeedsReplacement() {
+		return managed.ExternalUpdate{}, c.replace(ctx, cr, server)
+	}
 
 	current := *cr.Status.AtProvider.ServerParameters // What we have
 	target := cr.Spec.ForProvider                     // What we want
 
-	if _, _, err := c.hcloud.Client.Server.Update(ctx, server, hcloudsdk.ServerUpdateOpts{
-		Labels: hcloud.ApplyDefaultLabels(target.Labels),
+	labels, err := c.hcloud.UpdateLabels(server.Labels, target.Labels.Map(), probeLabels(target, server.Labels))
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if _, _, err := c.hcloud.Server.Update(ctx, server, hcloudsdk.ServerUpdateOpts{
+		Labels: labels,
 	}); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update server")
 	}
 
-	if current.PowerOn != target.PowerOn {
-		var action *hcloudsdk.Action
-		var err error
-		if target.PowerOn {
-			action, _, err = c.hcloud.Client.Server.Poweron(ctx, server)
-		} else {
-			action, _, err = c.hcloud.Client.Server.Poweroff(ctx, server)
+	if err := c.updateMemberships(ctx, cr, server); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if !isoUpToDate(target.ISO, server.ISO) {
+		if err := c.updateISO(ctx, server, target.ISO); err != nil {
+			return managed.ExternalUpdate{}, err
 		}
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, "failed to change power state")
+	}
+
+	now := time.Now()
+	powerOn, _, err := powerState(target, now)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	moved := true
+	if !placementGroupUpToDate(target.PlacementGroupID, server.PlacementGroup) {
+		if moved, err = c.updatePlacementGroup(ctx, cr, server, now); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if moved && target.PlacementGroupID != nil {
+			// Stopped to be added to the placement group
+			current.PowerOn = false
 		}
+	}
 
-		if err := c.hcloud.WaitForActionCompletion(ctx, action); err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, "error waiting for server to change power state")
+	// The power state is left alone while the server is shutting down to
+	// be moved
+	if moved && current.PowerOn != powerOn {
+		done, err := c.setPower(ctx, cr, server, powerOn, now)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if done {
+			current.PowerOn = powerOn
 		}
 	}
 
 	cr.Status.AtProvider.ServerParameters.Labels = target.Labels
-	cr.Status.AtProvider.ServerParameters.PowerOn = target.PowerOn
+	cr.Status.AtProvider.ServerParameters.PowerOn = current.PowerOn
+	cr.Status.AtProvider.ServerParameters.FirewallIDs = target.FirewallIDs
+	cr.Status.AtProvider.ServerParameters.NetworkIDs = target.NetworkIDs
+	cr.Status.AtProvider.ServerParameters.VolumeIDs = target.VolumeIDs
+	if moved {
+		cr.Status.AtProvider.ServerParameters.PlacementGroupID = target.PlacementGroupID
+	}
 	if err := c.kube.Status().Update(ctx, cr); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to save status")
 	}
 
-	return managed.ExternalUpdate{}, nil
+	// Operations run last, once the changes above are saved
+	conn, err := c.operate(ctx, cr, server)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	return managed.ExternalUpdate{ConnectionDetails: conn}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -329,20 +486,62 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(xpv1.Deleting())
 
-	_, _, err := c.hcloud.Client.Server.DeleteWithResult(ctx, &hcloudsdk.Server{
+	_, _, err := c.hcloud.Server.DeleteWithResult(ctx, &hcloudsdk.Server{
 		ID: cr.Status.AtProvider.ID,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to trigger server delete")
 	}
 
+	return c.deleteSSHKey(ctx, cr.Status.AtProvider.GeneratedSSHKeyID)
+}
+
+// replace deletes a server whose create-only parameters have changed, so
+// that it is created again with the new parameters. The delete is awaited so
+// that the name is free for the new server.
+func (c *external) replace(ctx context.Context, cr *v1alpha1.Server, server *hcloudsdk.Server) error {
+	result, _, err := c.hcloud.Server.DeleteWithResult(ctx, server)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete server for replacement")
+	}
+	if err := c.hcloud.WaitForActionCompletion(ctx, result.Action); err != nil {
+		return errors.Wrap(err, "failed to wait for server delete")
+	}
+
+	// The new server generates a key of its own
+	if err := c.deleteSSHKey(ctx, cr.Status.AtProvider.GeneratedSSHKeyID); err != nil {
+		return err
+	}
+
+	c.recorder.Event(cr, event.Normal(reasonReplaced, fmt.Sprintf("Deleted server %d to replace it as a create-only field changed", server.ID)))
+
+	cr.Status.AtProvider = v1alpha1.ServerObservation{}
+	cr.SetConditions(xpv1.Creating(), apisv1alpha1.ReplacementNotPending())
+	if err := c.kube.Status().Update(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to save status")
+	}
+
+	return nil
+}
+
+// deleteSSHKey removes a key generated for the server. Keys which are already
+// gone are ignored.
+func (c *external) deleteSSHKey(ctx context.Context, id int64) error {
+	if id == 0 {
+		return nil
+	}
+
+	if _, err := c.hcloud.SSHKey.Delete(ctx, &hcloudsdk.SSHKey{ID: id}); err != nil && !hcloudsdk.IsError(err, hcloudsdk.ErrorCodeNotFound) {
+		return errors.Wrap(err, "failed to delete generated ssh key")
+	}
+
 	return nil
 }
 
 func (c *external) getFirewalls(ctx context.Context, firewallIds []int64) ([]*hcloudsdk.ServerCreateFirewall, error) {
 	firewalls := []*hcloudsdk.ServerCreateFirewall{}
 	for _, firewall := range firewallIds {
-		f, _, err := c.hcloud.Client.Firewall.GetByID(ctx, firewall)
+		f, _, err := c.hcloud.Firewall.GetByID(ctx, firewall)
 		if err != nil {
 			return nil, errors.Wrap(err, "error getting firewall")
 		}
@@ -360,7 +559,7 @@ func (c *external) getFirewalls(ctx context.Context, firewallIds []int64) ([]*hc
 func (c *external) getNetworks(ctx context.Context, networkIDs []int64) ([]*hcloudsdk.Network, error) {
 	networks := []*hcloudsdk.Network{}
 	for _, network := range networkIDs {
-		n, _, err := c.hcloud.Client.Network.GetByID(ctx, network)
+		n, _, err := c.hcloud.Network.GetByID(ctx, network)
 		if err != nil {
 			return nil, errors.Wrap(err, "error getting network")
 		}
@@ -376,7 +575,7 @@ func (c *external) getNetworks(ctx context.Context, networkIDs []int64) ([]*hclo
 func (c *external) getVolumes(ctx context.Context, volumeIds []int64) ([]*hcloudsdk.Volume, error) {
 	volumes := []*hcloudsdk.Volume{}
 	for _, volume := range volumeIds {
-		v, _, err := c.hcloud.Client.Volume.GetByID(ctx, volume)
+		v, _, err := c.hcloud.Volume.GetByID(ctx, volume)
 		if err != nil {
 			return nil, errors.Wrap(err, "error getting volume")
 		}
@@ -17,12 +17,40 @@ limitations under the License.
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,7 +65,39 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func server(id int64, powerOn, observedPowerOn bool) *v1alpha1.Server {
+	return &v1alpha1.Server{
+		Spec: v1alpha1.ServerSpec{
+			ForProvider: v1alpha1.ServerParameters{PowerOn: powerOn},
+		},
+		Status: v1alpha1.ServerStatus{
+			AtProvider: v1alpha1.ServerObservation{
+				ID:               id,
+				ServerParameters: &v1alpha1.ServerParameters{PowerOn: observedPowerOn},
+			},
+		},
+	}
+}
+
 func TestObserve(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	labels := fake.ProviderLabels()
+
+	running := api.AddServer(schema.Server{Name: "running", Labels: labels})
+	drifted := api.AddServer(schema.Server{Name: "drifted"})
+	probing := api.AddServer(schema.Server{Name: "probing", Labels: map[string]string{
+		hcloud.ProviderLabel: hcloud.Provider,
+		"cloud-init":         "done",
+	}})
+	failing := api.AddServer(schema.Server{Name: "failing"})
+
+	initialising := fake.NewMockServerAPI(gomock.NewController(t))
+	initialising.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&hcloudsdk.Server{ID: 1, Status: hcloudsdk.ServerStatusInitializing, Labels: labels}, nil, nil).AnyTimes()
+
+	forbidden := api.Forbid(fmt.Sprintf("/servers/%d", failing.ID))
+
 	type fields struct {
 		hcloud *hcloud.Client
 	}
@@ -58,7 +118,121 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A server which does not exist should be reported as such",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(999, true, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A server matching the desired state should be up to date",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(running.ID, true, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Initialising": {
+			reason: "A server which is still being created should exist",
+			fields: fields{hcloud: &hcloud.Client{Server: initialising}},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(1, true, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LabelsDrifted": {
+			reason: "A server missing the provider labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(drifted.ID, true, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"ProbeLabel": {
+			reason: "A label set by the server for its readiness probe should not be treated as drift",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg: func() *v1alpha1.Server {
+					cr := server(probing.ID, true, true)
+					cr.Spec.ForProvider.ReadinessProbe = &apisv1alpha1.ReadinessProbe{
+						Type:  apisv1alpha1.ReadinessProbeLabel,
+						Label: &apisv1alpha1.ReadinessLabel{Key: "cloud-init"},
+					}
+					return cr
+				}(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"PowerStateChanged": {
+			reason: "A change to the desired power state should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(running.ID, false, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"ISONotAttached": {
+			reason: "A server without the wanted ISO attached should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg: func() *v1alpha1.Server {
+					cr := server(running.ID, true, true)
+					cr.Spec.ForProvider.ISO = hcloudsdk.Ptr("ubuntu-24.04-live-server-amd64.iso")
+					return cr
+				}(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"PowerScheduled": {
+			reason: "A server whose power schedule has turned it off should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg: func() *v1alpha1.Server {
+					cr := server(running.ID, true, true)
+					cr.Spec.ForProvider.PowerSchedule = &apisv1alpha1.PowerSchedule{On: "0 0 1 1 *", Off: "* * * * *"}
+					return cr
+				}(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"GetFailed": {
+			reason: "Errors from the API should be returned",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  server(failing.ID, true, true),
+			},
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				err: forbidden,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -68,9 +242,702 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			// Connection details are covered by TestConnectionDetails
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "ConnectionDetails")); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestCreate(t *testing.T) {
+	location := "fsn1"
+
+	type args struct {
+		ctx context.Context
+		mg  *v1alpha1.Server
+	}
+
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Created": {
+			reason: "A server should be created with the provider labels and its connection details returned",
+			args: args{
+				ctx: context.Background(),
+				mg: &v1alpha1.Server{
+					ObjectMeta: metav1.ObjectMeta{Name: "example"},
+					Spec: v1alpha1.ServerSpec{
+						ForProvider: v1alpha1.ServerParameters{
+							Image:            "ubuntu-22.04",
+							ServerType:       "cx22",
+							Location:         &location,
+							Architecture:     hcloudsdk.ArchitectureX86,
+							EnableIPv4:       true,
+							EnableIPv6:       true,
+							StartAfterCreate: true,
+						},
+					},
+				},
+			},
+		},
+		"UnknownServerType": {
+			reason: "A server type which does not exist should be reported",
+			args: args{
+				ctx: context.Background(),
+				mg: &v1alpha1.Server{
+					ObjectMeta: metav1.ObjectMeta{Name: "example"},
+					Spec: v1alpha1.ServerSpec{
+						ForProvider: v1alpha1.ServerParameters{
+							Image:        "ubuntu-22.04",
+							ServerType:   "cx9000",
+							Location:     &location,
+							Architecture: hcloudsdk.ArchitectureX86,
+						},
+					},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("unknown server type"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := fake.NewAPI()
+			defer api.Close()
+
+			e := external{kube: test.NewMockClient(), hcloud: api.Client()}
+			got, err := e.Create(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			s, ok := api.Server(tc.args.mg.Status.AtProvider.ID)
+			if !ok {
+				t.Fatalf("\n%s\ne.Create(...): server %d was not created\n", tc.reason, tc.args.mg.Status.AtProvider.ID)
+			}
+			if s.Labels[hcloud.ProviderLabel] != hcloud.Provider {
+				t.Errorf("\n%s\ne.Create(...): server is missing the %s label\n", tc.reason, hcloud.ProviderLabel)
+			}
+
+			want := managed.ConnectionDetails{
+				xpv1.ResourceCredentialsSecretEndpointKey: []byte(s.PublicNet.IPv4.IP),
+				xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
+				xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
+				xpv1.ResourceCredentialsSecretPasswordKey: []byte("fake-root-password"),
+				connectionKeyID:          []byte(strconv.FormatInt(s.ID, 10)),
+				connectionKeyName:        []byte("example"),
+				connectionKeyDatacenter:  []byte(s.Datacenter.Name),
+				connectionKeyIPv4:        []byte(s.PublicNet.IPv4.IP),
+				connectionKeyIPv6:        []byte(strings.TrimSuffix(s.PublicNet.IPv6.IP, "/64") + "1"),
+				connectionKeyIPv6Network: []byte(s.PublicNet.IPv6.IP),
+			}
+			if diff := cmp.Diff(want, got.ConnectionDetails); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	_, ipv6Network, _ := net.ParseCIDR("2001:db8::/64")
+
+	hostKey, err := newHostKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		server  *hcloudsdk.Server
+		hostKey ssh.PublicKey
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   managed.ConnectionDetails
+	}{
+		"Public": {
+			reason: "A server with public addresses should be reached on its IPv4 address",
+			args: args{
+				server: &hcloudsdk.Server{
+					ID:         1,
+					Name:       "public",
+					Datacenter: &hcloudsdk.Datacenter{Name: "fsn1-dc14"},
+					PublicNet: hcloudsdk.ServerPublicNet{
+						IPv4: hcloudsdk.ServerPublicNetIPv4{IP: net.ParseIP("203.0.113.1")},
+						IPv6: hcloudsdk.ServerPublicNetIPv6{IP: ipv6Network.IP, Network: ipv6Network},
+					},
+					PrivateNet: []hcloudsdk.ServerPrivateNet{
+						{IP: net.ParseIP("10.0.0.2")},
+						{IP: net.ParseIP("10.1.0.2")},
+					},
+				},
+				hostKey: hostKey,
+			},
+			want: managed.ConnectionDetails{
+				xpv1.ResourceCredentialsSecretEndpointKey: []byte("203.0.113.1"),
+				xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
+				xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
+				connectionKeyID:                           []byte("1"),
+				connectionKeyName:                         []byte("public"),
+				connectionKeyDatacenter:                   []byte("fsn1-dc14"),
+				connectionKeyIPv4:                         []byte("203.0.113.1"),
+				connectionKeyIPv6:                         []byte("2001:db8::1"),
+				connectionKeyIPv6Network:                  []byte("2001:db8::/64"),
+				connectionKeyPrivateIPs:                   []byte("10.0.0.2,10.1.0.2"),
+				connectionKeyKnownHosts:                   []byte("203.0.113.1,2001:db8::1,10.0.0.2,10.1.0.2 " + string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(hostKey))) + "\n"),
+			},
+		},
+		"IPv6Only": {
+			reason: "A server without a public IPv4 address should be reached on its IPv6 address",
+			args: args{
+				server: &hcloudsdk.Server{
+					ID:   2,
+					Name: "ipv6-only",
+					PublicNet: hcloudsdk.ServerPublicNet{
+						IPv6: hcloudsdk.ServerPublicNetIPv6{IP: ipv6Network.IP, Network: ipv6Network},
+					},
+				},
+			},
+			want: managed.ConnectionDetails{
+				xpv1.ResourceCredentialsSecretEndpointKey: []byte("2001:db8::1"),
+				xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
+				xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
+				connectionKeyID:                           []byte("2"),
+				connectionKeyName:                         []byte("ipv6-only"),
+				connectionKeyIPv6:                         []byte("2001:db8::1"),
+				connectionKeyIPv6Network:                  []byte("2001:db8::/64"),
+			},
+		},
+		"PrivateOnly": {
+			reason: "A server without public addresses should be reached on its first private IP",
+			args: args{
+				server: &hcloudsdk.Server{
+					ID:         3,
+					Name:       "private-only",
+					PrivateNet: []hcloudsdk.ServerPrivateNet{{IP: net.ParseIP("10.0.0.3")}},
+				},
+			},
+			want: managed.ConnectionDetails{
+				xpv1.ResourceCredentialsSecretEndpointKey: []byte("10.0.0.3"),
+				xpv1.ResourceCredentialsSecretUserKey:     []byte("root"),
+				xpv1.ResourceCredentialsSecretPortKey:     []byte("22"),
+				connectionKeyID:                           []byte("3"),
+				connectionKeyName:                         []byte("private-only"),
+				connectionKeyPrivateIPs:                   []byte("10.0.0.3"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := connectionDetails(tc.args.server, tc.args.hostKey)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nconnectionDetails(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGeneratedHostKey(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	location := "fsn1"
+	cr := &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: v1alpha1.ServerSpec{
+			ForProvider: v1alpha1.ServerParameters{
+				Image:           "ubuntu-22.04",
+				ServerType:      "cx22",
+				Location:        &location,
+				Architecture:    hcloudsdk.ArchitectureX86,
+				EnableIPv4:      true,
+				GenerateHostKey: true,
+			},
+		},
+	}
+
+	e := external{kube: test.NewMockClient(), hcloud: api.Client()}
+
+	created, err := e.Create(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Create(...): %s", err)
+	}
+
+	hostKey := parseHostKey(cr.Status.AtProvider.HostKey)
+	if hostKey == nil {
+		t.Fatalf("e.Create(...): want the generated host key in the status, got %q", cr.Status.AtProvider.HostKey)
+	}
+
+	observed, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+
+	s, _ := api.Server(cr.Status.AtProvider.ID)
+	want := s.PublicNet.IPv4.IP + " " + string(ssh.MarshalAuthorizedKey(hostKey))
+	if diff := cmp.Diff(want, string(created.ConnectionDetails[connectionKeyKnownHosts])); diff != "" {
+		t.Errorf("e.Create(...): -want known hosts, +got known hosts:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(want, string(observed.ConnectionDetails[connectionKeyKnownHosts])); diff != "" {
+		t.Errorf("e.Observe(...): -want known hosts, +got known hosts:\n%s\n", diff)
+	}
+}
+
+func TestHostKeyUserData(t *testing.T) {
+	public, private, err := generateSSHKey("example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part := hostKeyUserData(public, private)
+	if diff := cmp.Diff("text/cloud-config", detectContentType(part.content)); diff != "" {
+		t.Errorf("hostKeyUserData(...): -want content type, +got content type:\n%s\n", diff)
+	}
+
+	var config struct {
+		SSHKeys map[string]string `json:"ssh_keys"`
+	}
+	if err := yaml.Unmarshal([]byte(part.content), &config); err != nil {
+		t.Fatalf("hostKeyUserData(...): cannot parse cloud-config: %s", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey([]byte(config.SSHKeys["ed25519_private"]))
+	if err != nil {
+		t.Fatalf("hostKeyUserData(...): cannot parse the private host key: %s", err)
+	}
+	if diff := cmp.Diff(public, config.SSHKeys["ed25519_public"]); diff != "" {
+		t.Errorf("hostKeyUserData(...): -want public host key, +got public host key:\n%s\n", diff)
+	}
+	if !strings.HasPrefix(public, string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(signer.PublicKey())))) {
+		t.Error("hostKeyUserData(...): the private host key does not match the public host key")
+	}
+}
+
+func newSigner() (ssh.Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.NewSignerFromKey(key)
+}
+
+func newHostKey() (ssh.PublicKey, error) {
+	signer, err := newSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.PublicKey(), nil
+}
+
+func TestGeneratedSSHKey(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	location := "fsn1"
+	cr := &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: v1alpha1.ServerSpec{
+			ForProvider: v1alpha1.ServerParameters{
+				Image:          "ubuntu-22.04",
+				ServerType:     "cx22",
+				Location:       &location,
+				Architecture:   hcloudsdk.ArchitectureX86,
+				EnableIPv4:     true,
+				GenerateSSHKey: true,
+			},
+		},
+	}
+
+	e := external{kube: test.NewMockClient(), hcloud: api.Client()}
+
+	got, err := e.Create(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Create(...): %s", err)
+	}
+
+	keys := api.SSHKeys()
+	if len(keys) != 1 || keys[0].ID != cr.Status.AtProvider.GeneratedSSHKeyID {
+		t.Fatalf("e.Create(...): want the generated key %d to be uploaded, got %v", cr.Status.AtProvider.GeneratedSSHKeyID, keys)
+	}
+	if diff := cmp.Diff(keys[0].PublicKey, string(got.ConnectionDetails[connectionKeyPublicKey])); diff != "" {
+		t.Errorf("e.Create(...): -uploaded public key, +published public key:\n%s\n", diff)
+	}
+	if _, ok := got.ConnectionDetails[xpv1.ResourceCredentialsSecretPasswordKey]; ok {
+		t.Error("e.Create(...): a server created with an SSH key should not have a root password")
+	}
+
+	signer, err := ssh.ParsePrivateKey(got.ConnectionDetails[connectionKeyPrivateKey])
+	if err != nil {
+		t.Fatalf("e.Create(...): cannot parse the published private key: %s", err)
+	}
+	if !strings.HasPrefix(keys[0].PublicKey, string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(signer.PublicKey())))) {
+		t.Error("e.Create(...): the published private key does not match the uploaded public key")
+	}
+
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): %s", err)
+	}
+	if keys := api.SSHKeys(); len(keys) != 0 {
+		t.Errorf("e.Delete(...): want the generated key to be deleted, got %v", keys)
+	}
+
+	// Delete is called again until the server is gone, by which time the key
+	// no longer exists
+	if err := e.deleteSSHKey(context.Background(), cr.Status.AtProvider.GeneratedSSHKeyID); err != nil {
+		t.Errorf("e.deleteSSHKey(...): %s", err)
+	}
+}
+
+func TestUserData(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Secret:
+				if key != (client.ObjectKey{Namespace: "default", Name: "bootstrap"}) {
+					return kerrors.NewNotFound(corev1.Resource("secrets"), key.Name)
+				}
+				o.Data = map[string][]byte{"token": []byte("#!/bin/sh\necho secret-token\n")}
+			case *corev1.ConfigMap:
+				if key != (client.ObjectKey{Namespace: "default", Name: "cloud-init"}) {
+					return kerrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+				}
+				o.Data = map[string]string{
+					"config":   "#cloud-config\nhostname: {{ .Name }}\nenv: {{ .Labels.env }}\nnetworks: {{ .NetworkIDs }}\n",
+					"large":    strings.Repeat("#", maxUserDataSize+1),
+					"template": "{{ .Missing }}",
+				}
+			}
+			return nil
+		},
+	}
+
+	secret := func(key string) apisv1alpha1.UserDataSource {
+		return apisv1alpha1.UserDataSource{
+			SecretKeyRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "bootstrap", Namespace: "default"},
+				Key:             key,
+			},
+		}
+	}
+	configMap := func(key string, template bool) apisv1alpha1.UserDataSource {
+		return apisv1alpha1.UserDataSource{
+			ConfigMapKeyRef: &apisv1alpha1.ConfigMapKeySelector{Name: "cloud-init", Namespace: "default", Key: key},
+			Template:        template,
+		}
+	}
+	server := func(userData string, from ...apisv1alpha1.UserDataSource) *v1alpha1.Server {
+		return &v1alpha1.Server{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: v1alpha1.ServerSpec{
+				ForProvider: v1alpha1.ServerParameters{
+					Labels:       apisv1alpha1.Labels{"env": "test"},
+					NetworkIDs:   []int64{1, 2},
+					UserData:     userData,
+					UserDataFrom: from,
+				},
+			},
+		}
+	}
+
+	type want struct {
+		userData string
+		parts    []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.Server
+		want   want
+	}{
+		"None": {
+			reason: "A server without user data should have none",
+			cr:     server(""),
+		},
+		"Inline": {
+			reason: "Inline user data should be used as it is",
+			cr:     server("#cloud-config\n"),
+			want:   want{userData: "#cloud-config\n"},
+		},
+		"Secret": {
+			reason: "A single source should be used without being assembled into a multipart document",
+			cr:     server("", secret("token")),
+			want:   want{userData: "#!/bin/sh\necho secret-token\n"},
+		},
+		"Template": {
+			reason: "A templated source should be rendered with the server's values",
+			cr:     server("", configMap("config", true)),
+			want:   want{userData: "#cloud-config\nhostname: example\nenv: test\nnetworks: [1 2]\n"},
+		},
+		"NotTemplated": {
+			reason: "A source which is not templated should be used as it is",
+			cr:     server("", configMap("template", false)),
+			want:   want{userData: "{{ .Missing }}"},
+		},
+		"Multipart": {
+			reason: "Several parts should be assembled in order into a multipart document",
+			cr:     server("#cloud-config\n", configMap("config", true), secret("token")),
+			want: want{parts: []string{
+				"text/cloud-config: #cloud-config\n",
+				"text/cloud-config: #cloud-config\nhostname: example\nenv: test\nnetworks: [1 2]\n",
+				"text/x-shellscript: #!/bin/sh\necho secret-token\n",
+			}},
+		},
+		"MissingKey": {
+			reason: "A key which does not exist should be reported",
+			cr:     server("", secret("missing")),
+			want:   want{err: errors.Wrap(fmt.Errorf("secret default/bootstrap has no key missing"), "failed to get userDataFrom[0]")},
+		},
+		"TooLarge": {
+			reason: "User data larger than Hetzner allows should be reported",
+			cr:     server("", configMap("large", false)),
+			want:   want{err: fmt.Errorf("user data is %d bytes, more than the %d allowed", maxUserDataSize+1, maxUserDataSize)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: kube}
+			got, err := e.userData(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ne.userData(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+
+			if tc.want.parts == nil {
+				if diff := cmp.Diff(tc.want.userData, got); diff != "" {
+					t.Errorf("\n%s\ne.userData(...): -want, +got:\n%s\n", tc.reason, diff)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tc.want.parts, multipartContents(t, got)); diff != "" {
+				t.Errorf("\n%s\ne.userData(...): -want parts, +got parts:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// multipartContents returns the content type and content of each part of a
+// multipart user data document
+func multipartContents(t *testing.T, userData string) []string {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(userData))
+	if err != nil {
+		t.Fatalf("cannot read multipart user data: %s", err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("cannot parse multipart content type: %s", err)
+	}
+
+	parts := make([]string, 0)
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("cannot read user data part: %s", err)
+		}
+
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		content, _ := io.ReadAll(part)
+		parts = append(parts, contentType+": "+string(content))
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	bannerPort := listen(t, "SSH-2.0-OpenSSH_9.6\r\n")
+	otherPort := listen(t, "HTTP/1.1 400 Bad Request\r\n")
+
+	probed := func(p *apisv1alpha1.ReadinessProbe, conditions ...xpv1.Condition) *v1alpha1.Server {
+		cr := &v1alpha1.Server{
+			Spec: v1alpha1.ServerSpec{
+				ForProvider: v1alpha1.ServerParameters{ReadinessProbe: p},
+			},
+		}
+		cr.SetConditions(conditions...)
+		return cr
+	}
+	running := func(created time.Time, labels map[string]string) *hcloudsdk.Server {
+		return &hcloudsdk.Server{
+			Status:  hcloudsdk.ServerStatusRunning,
+			Created: created,
+			Labels:  labels,
+			PublicNet: hcloudsdk.ServerPublicNet{
+				IPv4: hcloudsdk.ServerPublicNetIPv4{IP: net.ParseIP("127.0.0.1")},
+			},
+		}
+	}
+	label := &apisv1alpha1.ReadinessProbe{
+		Type:  apisv1alpha1.ReadinessProbeLabel,
+		Label: &apisv1alpha1.ReadinessLabel{Key: "cloud-init", Value: hcloudsdk.Ptr("done")},
+	}
+
+	type args struct {
+		cr     *v1alpha1.Server
+		server *hcloudsdk.Server
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   xpv1.Condition
+	}{
+		"NoProbe": {
+			reason: "A server without a readiness probe should be available once running",
+			args: args{
+				cr:     probed(nil),
+				server: running(time.Now(), nil),
+			},
+			want: xpv1.Available(),
+		},
+		"AlreadyReady": {
+			reason: "A server which has passed its probe should not be probed again",
+			args: args{
+				cr:     probed(label, xpv1.Available()),
+				server: running(time.Now(), nil),
+			},
+			want: xpv1.Available(),
+		},
+		"LabelSet": {
+			reason: "A server with the probe's label should be available",
+			args: args{
+				cr:     probed(label),
+				server: running(time.Now(), map[string]string{"cloud-init": "done"}),
+			},
+			want: xpv1.Available(),
+		},
+		"LabelValueDiffers": {
+			reason: "A server whose label has another value should still be waited for",
+			args: args{
+				cr:     probed(label),
+				server: running(time.Now(), map[string]string{"cloud-init": "running"}),
+			},
+			want: xpv1.Creating().WithMessage("waiting for the Label readiness probe to pass"),
+		},
+		"TCP": {
+			reason: "A server accepting connections on the port should pass a TCP probe",
+			args: args{
+				cr:     probed(&apisv1alpha1.ReadinessProbe{Type: apisv1alpha1.ReadinessProbeTCP, Port: otherPort}),
+				server: running(time.Now(), nil),
+			},
+			want: xpv1.Available(),
+		},
+		"SSH": {
+			reason: "A server answering with an SSH banner should pass an SSH probe",
+			args: args{
+				cr:     probed(&apisv1alpha1.ReadinessProbe{Type: apisv1alpha1.ReadinessProbeSSH, Port: bannerPort}),
+				server: running(time.Now(), nil),
+			},
+			want: xpv1.Available(),
+		},
+		"NotSSH": {
+			reason: "A server answering with something other than SSH should fail an SSH probe",
+			args: args{
+				cr:     probed(&apisv1alpha1.ReadinessProbe{Type: apisv1alpha1.ReadinessProbeSSH, Port: otherPort}),
+				server: running(time.Now(), nil),
+			},
+			want: xpv1.Creating().WithMessage("waiting for the SSH readiness probe to pass"),
+		},
+		"TimedOut": {
+			reason: "A server which has not passed its probe within the timeout should be unavailable",
+			args: args{
+				cr:     probed(&apisv1alpha1.ReadinessProbe{Type: apisv1alpha1.ReadinessProbeLabel, Label: label.Label, TimeoutSeconds: 60}),
+				server: running(time.Now().Add(-time.Hour), nil),
+			},
+			want: xpv1.Unavailable().WithMessage("Label readiness probe did not pass within 1m0s of the server being created"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := readiness(context.Background(), tc.args.cr, tc.args.server)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nreadiness(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProbeDeadline(t *testing.T) {
+	// A server which accepts connections but never sends a banner
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		<-done
+		_ = conn.Close()
+	}()
+
+	p := &apisv1alpha1.ReadinessProbe{Type: apisv1alpha1.ReadinessProbeSSH, Port: l.Addr().(*net.TCPAddr).Port}
+	server := &hcloudsdk.Server{
+		PublicNet: hcloudsdk.ServerPublicNet{
+			IPv4: hcloudsdk.ServerPublicNetIPv4{IP: net.ParseIP("127.0.0.1")},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if probe(ctx, p, server) {
+		t.Error("probe(...): a server without a banner should fail an SSH probe")
+	}
+	if elapsed := time.Since(start); elapsed > probeAttemptTimeout {
+		t.Errorf("probe(...): want the probe bounded by the context, took %s", elapsed)
+	}
+}
+
+// listen starts a server on a local port which writes the greeting to every
+// connection, returning the port
+func listen(t *testing.T, greeting string) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte(greeting))
+			_ = conn.Close()
+		}
+	}()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
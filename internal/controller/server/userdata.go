@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// Hetzner rejects user data larger than 32KiB
+const maxUserDataSize = 32 * 1024
+
+// Content types cloud-init recognises from the first line of a part
+var contentTypes = []struct {
+	prefix      string
+	contentType string
+}{
+	{"#cloud-config-archive", "text/cloud-config-archive"},
+	{"#cloud-config", "text/cloud-config"},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include-once", "text/x-include-once-url"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{"## template: jinja", "text/jinja2"},
+	{"#!", "text/x-shellscript"},
+}
+
+// userDataValues are available to user data templates
+type userDataValues struct {
+	Name             string
+	Labels           map[string]string
+	Image            string
+	ServerType       string
+	Location         string
+	Datacenter       string
+	FirewallIDs      []int64
+	NetworkIDs       []int64
+	PlacementGroupID int64
+	VolumeIDs        []int64
+}
+
+type userDataPart struct {
+	contentType string
+	content     string
+}
+
+// userData returns the user data for the server, resolving userDataFrom and
+// adding any extra parts after them. Several parts are assembled into a
+// multipart cloud-init document.
+func (c *external) userData(ctx context.Context, cr *v1alpha1.Server, extra ...userDataPart) (string, error) {
+	p := cr.Spec.ForProvider
+
+	parts := make([]userDataPart, 0, len(p.UserDataFrom)+len(extra)+1)
+	if p.UserData != "" {
+		parts = append(parts, userDataPart{content: p.UserData})
+	}
+
+	for i, source := range p.UserDataFrom {
+		content, err := c.userDataSource(ctx, source)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get userDataFrom[%d]", i)
+		}
+
+		if source.Template {
+			if content, err = renderUserData(cr, content); err != nil {
+				return "", errors.Wrapf(err, "failed to render userDataFrom[%d]", i)
+			}
+		}
+
+		parts = append(parts, userDataPart{contentType: source.ContentType, content: content})
+	}
+	parts = append(parts, extra...)
+
+	var userData string
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		userData = parts[0].content
+	default:
+		var err error
+		if userData, err = multipartUserData(parts); err != nil {
+			return "", err
+		}
+	}
+
+	if len(userData) > maxUserDataSize {
+		return "", fmt.Errorf("user data is %d bytes, more than the %d allowed", len(userData), maxUserDataSize)
+	}
+
+	return userData, nil
+}
+
+func (c *external) userDataSource(ctx context.Context, source apisv1alpha1.UserDataSource) (string, error) {
+	switch {
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+
+		secret := &corev1.Secret{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+			return "", errors.Wrap(err, "cannot get secret")
+		}
+
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+		return string(value), nil
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+
+		configMap := &corev1.ConfigMap{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, configMap); err != nil {
+			return "", errors.Wrap(err, "cannot get config map")
+		}
+
+		if value, ok := configMap.Data[ref.Key]; ok {
+			return value, nil
+		}
+		if value, ok := configMap.BinaryData[ref.Key]; ok {
+			return string(value), nil
+		}
+		return "", fmt.Errorf("config map %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	return "", fmt.Errorf("no secretKeyRef or configMapKeyRef set")
+}
+
+// renderUserData executes content as a Go template with the server's values
+func renderUserData(cr *v1alpha1.Server, content string) (string, error) {
+	p := cr.Spec.ForProvider
+
+	tmpl, err := template.New(cr.GetName()).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	values := userDataValues{
+		Name:        cr.GetName(),
+		Labels:      p.Labels.Map(),
+		Image:       p.Image,
+		ServerType:  p.ServerType,
+		FirewallIDs: p.FirewallIDs,
+		NetworkIDs:  p.NetworkIDs,
+		VolumeIDs:   p.VolumeIDs,
+	}
+	if p.Location != nil {
+		values.Location = *p.Location
+	}
+	if p.Datacenter != nil {
+		values.Datacenter = *p.Datacenter
+	}
+	if p.PlacementGroupID != nil {
+		values.PlacementGroupID = *p.PlacementGroupID
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, values); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// multipartUserData assembles the parts into a MIME multipart document, which
+// cloud-init processes part by part
+func multipartUserData(parts []userDataPart) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	for i, part := range parts {
+		contentType := part.contentType
+		if contentType == "" {
+			contentType = detectContentType(part.content)
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", contentType+`; charset="utf-8"`)
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Transfer-Encoding", "7bit")
+		header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="part-%03d"`, i+1))
+
+		pw, err := w.CreatePart(header)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create user data part")
+		}
+		if _, err := pw.Write([]byte(part.content)); err != nil {
+			return "", errors.Wrap(err, "failed to write user data part")
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to assemble user data")
+	}
+
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\nMIME-Version: 1.0\n\n%s", w.Boundary(), body.String()), nil
+}
+
+func detectContentType(content string) string {
+	for _, t := range contentTypes {
+		if strings.HasPrefix(content, t.prefix) {
+			return t.contentType
+		}
+	}
+
+	return "text/plain"
+}
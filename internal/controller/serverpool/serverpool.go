@@ -0,0 +1,615 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverpool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/internal/features"
+)
+
+const (
+	errNotServerPool = "managed resource is not a ServerPool custom resource"
+	errTrackPCUsage  = "cannot track ProviderConfig usage"
+
+	// LabelPool is set on each server to the name of its pool
+	LabelPool = "cloud.hetzner.crossplane.io/server-pool"
+
+	// LabelIndex is set on each server to its index in the pool
+	LabelIndex = "cloud.hetzner.crossplane.io/server-pool-index"
+
+	// LabelTemplateHash is set on each server to the hash of the template
+	// it was created from
+	LabelTemplateHash = "cloud.hetzner.crossplane.io/template-hash"
+)
+
+// Setup adds a controller that reconciles ServerPool managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.ServerPoolGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ServerPoolGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:  mgr.GetClient(),
+			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+		}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	// Changes to the servers, such as one becoming ready, are reconciled
+	// straight away
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ServerPool{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Owns(&v1alpha1.Server{}).
+		Owns(&v1alpha1.PlacementGroup{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube  client.Client
+	usage resource.Tracker
+}
+
+// Connect produces an ExternalClient which manages the pool's servers. They
+// are Kubernetes objects, so unlike the other kinds no Hetzner credentials
+// are needed, but the ProviderConfig is tracked as the servers use it.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.ServerPool); !ok {
+		return nil, errors.New(errNotServerPool)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	return &external{kube: c.kube}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes the
+// servers of a pool to ensure they reflect the pool's desired state.
+type external struct {
+	kube client.Client
+}
+
+// Observe reports the pool as existing for as long as it has servers or a
+// placement group, so everything but deletion is handled by Update.
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ServerPool)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotServerPool)
+	}
+
+	servers, err := c.servers(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	placementGroup, err := c.placementGroup(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: len(servers) > 0 || placementGroup != nil}, nil
+	}
+
+	hash, err := templateHash(cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.AtProvider = observe(servers, hash)
+
+	p := cr.Spec.ForProvider
+	if o := cr.Status.AtProvider; o.Replicas == p.Replicas && o.AvailableReplicas == p.Replicas {
+		cr.SetConditions(xpv1.Available())
+	} else {
+		cr.SetConditions(xpv1.Creating().WithMessage(fmt.Sprintf("%d of %d servers available", o.AvailableReplicas, p.Replicas)))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: upToDate(cr, servers, placementGroup, hash),
+	}, nil
+}
+
+// Create is never called as Observe always reports the pool as existing
+func (c *external) Create(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
+
+// Update makes one pass towards the desired servers. Servers are created and
+// deleted to match the replicas, existing servers are updated in place, and
+// those created from an old template are deleted so they are created again,
+// as long as no more than maxUnavailable servers are unavailable.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) { //nolint:gocyclo
+	cr, ok := mg.(*v1alpha1.ServerPool)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotServerPool)
+	}
+
+	p := cr.Spec.ForProvider
+
+	hash, err := templateHash(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	servers, err := c.servers(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if err := c.reconcilePlacementGroup(ctx, cr, servers); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	byIndex := map[int32]*v1alpha1.Server{}
+	unavailable := int32(0)
+	outdated := make([]*v1alpha1.Server, 0)
+	for i := range servers {
+		s := &servers[i]
+		index, ok := serverIndex(s)
+
+		switch {
+		case !ok || index >= p.Replicas:
+			// Scale down
+			if err := c.deleteServer(ctx, s); err != nil {
+				return managed.ExternalUpdate{}, err
+			}
+			continue
+		case meta.WasDeleted(s):
+			byIndex[index] = s
+			unavailable++
+			continue
+		}
+
+		byIndex[index] = s
+		if !ready(s) {
+			unavailable++
+		}
+
+		if s.GetLabels()[LabelTemplateHash] != hash {
+			outdated = append(outdated, s)
+			continue
+		}
+
+		if want := desiredServer(cr, index, hash); !inPlaceUpToDate(s, want) {
+			updateInPlace(s, want)
+			if err := c.kube.Update(ctx, s); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrapf(err, "cannot update server %s", s.GetName())
+			}
+		}
+	}
+
+	// Scale up, filling any gaps left by servers that were replaced
+	for index := int32(0); index < p.Replicas; index++ {
+		if _, ok := byIndex[index]; ok {
+			continue
+		}
+		unavailable++
+
+		if err := c.kube.Create(ctx, desiredServer(cr, index, hash)); resource.Ignore(kerrors.IsAlreadyExists, err) != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, "cannot create server %d", index)
+		}
+	}
+
+	// Servers which are not ready can be replaced straight away, the others
+	// only while there is room to make them unavailable
+	maxUnavailable := max(p.MaxUnavailable, 1)
+	sort.SliceStable(outdated, func(i, j int) bool {
+		return !ready(outdated[i]) && ready(outdated[j])
+	})
+	for _, s := range outdated {
+		if ready(s) {
+			if unavailable >= maxUnavailable {
+				break
+			}
+			unavailable++
+		}
+
+		if err := c.deleteServer(ctx, s); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete removes the servers of the pool, and then its placement group once
+// they are gone.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ServerPool)
+	if !ok {
+		return errors.New(errNotServerPool)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	servers, err := c.servers(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for i := range servers {
+		if err := c.deleteServer(ctx, &servers[i]); err != nil {
+			return err
+		}
+	}
+
+	if len(servers) > 0 {
+		return nil
+	}
+
+	return errors.Wrap(resource.IgnoreNotFound(c.kube.Delete(ctx, &v1alpha1.PlacementGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: placementGroupName(cr)},
+	})), "cannot delete placement group")
+}
+
+// reconcilePlacementGroup creates the pool's spread placement group, or
+// deletes it once spread is turned off and no server is in it
+func (c *external) reconcilePlacementGroup(ctx context.Context, cr *v1alpha1.ServerPool, servers []v1alpha1.Server) error {
+	name := placementGroupName(cr)
+
+	if cr.Spec.ForProvider.Spread {
+		placementGroup := &v1alpha1.PlacementGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{LabelPool: cr.GetName()},
+			},
+			Spec: v1alpha1.PlacementGroupSpec{
+				ResourceSpec: xpv1.ResourceSpec{
+					ProviderConfigReference: cr.GetProviderConfigReference(),
+					DeletionPolicy:          cr.GetDeletionPolicy(),
+					ManagementPolicies:      cr.GetManagementPolicies(),
+				},
+				Project:     cr.Spec.Project,
+				ForProvider: v1alpha1.PlacementGroupParameters{Type: hcloudsdk.PlacementGroupTypeSpread},
+			},
+		}
+		meta.AddOwnerReference(placementGroup, meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.ServerPoolGroupVersionKind)))
+
+		return errors.Wrap(resource.Ignore(kerrors.IsAlreadyExists, c.kube.Create(ctx, placementGroup)), "cannot create placement group")
+	}
+
+	for _, s := range servers {
+		if ref := s.Spec.ForProvider.PlacementGroupIDRef; ref != nil && ref.Name == name {
+			return nil
+		}
+	}
+
+	return errors.Wrap(resource.IgnoreNotFound(c.kube.Delete(ctx, &v1alpha1.PlacementGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	})), "cannot delete placement group")
+}
+
+// servers returns the servers of the pool, ordered by name
+func (c *external) servers(ctx context.Context, cr *v1alpha1.ServerPool) ([]v1alpha1.Server, error) {
+	l := &v1alpha1.ServerList{}
+	if err := c.kube.List(ctx, l, client.MatchingLabels{LabelPool: cr.GetName()}); err != nil {
+		return nil, errors.Wrap(err, "cannot list servers")
+	}
+
+	// Only servers the pool controls belong to it
+	servers := make([]v1alpha1.Server, 0, len(l.Items))
+	for _, s := range l.Items {
+		if ref := metav1.GetControllerOf(&s); ref != nil && ref.UID == cr.GetUID() {
+			servers = append(servers, s)
+		}
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].GetName() < servers[j].GetName()
+	})
+
+	return servers, nil
+}
+
+// placementGroup returns the pool's placement group, or nil if it has none
+func (c *external) placementGroup(ctx context.Context, cr *v1alpha1.ServerPool) (*v1alpha1.PlacementGroup, error) {
+	placementGroup := &v1alpha1.PlacementGroup{}
+	err := c.kube.Get(ctx, types.NamespacedName{Name: placementGroupName(cr)}, placementGroup)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get placement group")
+	}
+
+	if ref := metav1.GetControllerOf(placementGroup); ref == nil || ref.UID != cr.GetUID() {
+		return nil, nil
+	}
+
+	return placementGroup, nil
+}
+
+func (c *external) deleteServer(ctx context.Context, s *v1alpha1.Server) error {
+	if meta.WasDeleted(s) {
+		return nil
+	}
+
+	return errors.Wrapf(resource.IgnoreNotFound(c.kube.Delete(ctx, s)), "cannot delete server %s", s.GetName())
+}
+
+// observe counts the servers of the pool
+func observe(servers []v1alpha1.Server, hash string) v1alpha1.ServerPoolObservation {
+	o := v1alpha1.ServerPoolObservation{}
+
+	for i := range servers {
+		s := &servers[i]
+		if meta.WasDeleted(s) {
+			continue
+		}
+
+		o.Replicas++
+		o.Servers = append(o.Servers, s.GetName())
+
+		updated := s.GetLabels()[LabelTemplateHash] == hash
+		if ready(s) {
+			o.ReadyReplicas++
+		}
+		if updated {
+			o.UpdatedReplicas++
+		}
+		if ready(s) && updated {
+			o.AvailableReplicas++
+		}
+	}
+
+	return o
+}
+
+// upToDate reports whether the pool has exactly the servers it wants
+func upToDate(cr *v1alpha1.ServerPool, servers []v1alpha1.Server, placementGroup *v1alpha1.PlacementGroup, hash string) bool {
+	p := cr.Spec.ForProvider
+
+	if p.Spread != (placementGroup != nil) {
+		return false
+	}
+	if int32(len(servers)) != p.Replicas {
+		return false
+	}
+
+	for i := range servers {
+		s := &servers[i]
+
+		index, ok := serverIndex(s)
+		if !ok || index >= p.Replicas || meta.WasDeleted(s) || s.GetLabels()[LabelTemplateHash] != hash {
+			return false
+		}
+		if !inPlaceUpToDate(s, desiredServer(cr, index, hash)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// desiredServer returns the server the pool wants at the index
+func desiredServer(cr *v1alpha1.ServerPool, index int32, hash string) *v1alpha1.Server {
+	t := cr.Spec.ForProvider.Template
+
+	s := &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: serverName(cr, index),
+			Labels: map[string]string{
+				LabelPool:         cr.GetName(),
+				LabelIndex:        strconv.Itoa(int(index)),
+				LabelTemplateHash: hash,
+			},
+		},
+		Spec: v1alpha1.ServerSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: cr.GetProviderConfigReference(),
+				DeletionPolicy:          cr.GetDeletionPolicy(),
+				ManagementPolicies:      cr.GetManagementPolicies(),
+			},
+			Project:     cr.Spec.Project,
+			ForProvider: *t.ForProvider.DeepCopy(),
+		},
+	}
+
+	if ns := t.WriteConnectionSecretsToNamespace; ns != nil {
+		s.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: s.GetName(), Namespace: *ns})
+	}
+
+	if cr.Spec.ForProvider.Spread {
+		s.Spec.ForProvider.PlacementGroupID = nil
+		s.Spec.ForProvider.PlacementGroupIDSelector = nil
+		s.Spec.ForProvider.PlacementGroupIDRef = &xpv1.Reference{Name: placementGroupName(cr)}
+	}
+
+	meta.AddOwnerReference(s, meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.ServerPoolGroupVersionKind)))
+
+	return s
+}
+
+// inPlaceUpToDate compares the fields which are changed without replacing
+// the server
+func inPlaceUpToDate(s, want *v1alpha1.Server) bool {
+	p, w := s.Spec.ForProvider, want.Spec.ForProvider
+
+	return reflect.DeepEqual(p.Labels, w.Labels) &&
+		p.PowerOn == w.PowerOn &&
+		reflect.DeepEqual(p.PowerSchedule, w.PowerSchedule) &&
+		reflect.DeepEqual(p.PowerOff, w.PowerOff) &&
+		reflect.DeepEqual(p.ReadinessProbe, w.ReadinessProbe) &&
+		reflect.DeepEqual(p.ISO, w.ISO) &&
+		idsUpToDate(p.FirewallIDs, w.FirewallIDs, p.FirewallIDRefs, w.FirewallIDRefs, p.FirewallIDSelector, w.FirewallIDSelector) &&
+		idsUpToDate(p.NetworkIDs, w.NetworkIDs, p.NetworkIDRefs, w.NetworkIDRefs, p.NetworkIDSelector, w.NetworkIDSelector) &&
+		idsUpToDate(p.VolumeIDs, w.VolumeIDs, p.VolumeIDRefs, w.VolumeIDRefs, p.VolumeIDSelector, w.VolumeIDSelector) &&
+		placementGroupUpToDate(p, w) &&
+		s.GetDeletionPolicy() == want.GetDeletionPolicy() &&
+		reflect.DeepEqual(s.GetWriteConnectionSecretToReference(), want.GetWriteConnectionSecretToReference())
+}
+
+func updateInPlace(s, want *v1alpha1.Server) {
+	p, w := &s.Spec.ForProvider, want.Spec.ForProvider
+
+	p.Labels = w.Labels
+	p.PowerOn = w.PowerOn
+	p.PowerSchedule = w.PowerSchedule
+	p.PowerOff = w.PowerOff
+	p.ReadinessProbe = w.ReadinessProbe
+	p.ISO = w.ISO
+
+	// IDs resolved by the server are only reset when their references or
+	// selector change, so that they are resolved again
+	if !idsUpToDate(p.FirewallIDs, w.FirewallIDs, p.FirewallIDRefs, w.FirewallIDRefs, p.FirewallIDSelector, w.FirewallIDSelector) {
+		p.FirewallIDs, p.FirewallIDRefs, p.FirewallIDSelector = w.FirewallIDs, w.FirewallIDRefs, w.FirewallIDSelector
+	}
+	if !idsUpToDate(p.NetworkIDs, w.NetworkIDs, p.NetworkIDRefs, w.NetworkIDRefs, p.NetworkIDSelector, w.NetworkIDSelector) {
+		p.NetworkIDs, p.NetworkIDRefs, p.NetworkIDSelector = w.NetworkIDs, w.NetworkIDRefs, w.NetworkIDSelector
+	}
+	if !idsUpToDate(p.VolumeIDs, w.VolumeIDs, p.VolumeIDRefs, w.VolumeIDRefs, p.VolumeIDSelector, w.VolumeIDSelector) {
+		p.VolumeIDs, p.VolumeIDRefs, p.VolumeIDSelector = w.VolumeIDs, w.VolumeIDRefs, w.VolumeIDSelector
+	}
+	if !placementGroupUpToDate(*p, w) {
+		p.PlacementGroupID, p.PlacementGroupIDRef, p.PlacementGroupIDSelector = w.PlacementGroupID, w.PlacementGroupIDRef, w.PlacementGroupIDSelector
+	}
+
+	s.SetDeletionPolicy(want.GetDeletionPolicy())
+	s.SetWriteConnectionSecretToReference(want.GetWriteConnectionSecretToReference())
+}
+
+// idsUpToDate compares a server's IDs with those wanted. IDs which the
+// server resolves from references or a selector are not in the template, so
+// only the references and selector are compared for them.
+func idsUpToDate(ids, want []int64, refs, wantRefs []xpv1.Reference, selector, wantSelector *xpv1.Selector) bool {
+	if (len(refs) > 0 || len(wantRefs) > 0) && !reflect.DeepEqual(refs, wantRefs) {
+		return false
+	}
+	if !reflect.DeepEqual(selector, wantSelector) {
+		return false
+	}
+	if len(wantRefs) > 0 || wantSelector != nil {
+		return true
+	}
+
+	return slices.Equal(ids, want)
+}
+
+// placementGroupUpToDate compares a server's placement group with the one
+// wanted, in the same way as idsUpToDate
+func placementGroupUpToDate(p, want v1alpha1.ServerParameters) bool {
+	if !reflect.DeepEqual(p.PlacementGroupIDRef, want.PlacementGroupIDRef) || !reflect.DeepEqual(p.PlacementGroupIDSelector, want.PlacementGroupIDSelector) {
+		return false
+	}
+	if want.PlacementGroupIDRef != nil || want.PlacementGroupIDSelector != nil {
+		return true
+	}
+
+	return reflect.DeepEqual(p.PlacementGroupID, want.PlacementGroupID)
+}
+
+// templateHash identifies the servers created from the current template. The
+// fields changed in place are left out, so changing them does not replace
+// the servers.
+func templateHash(cr *v1alpha1.ServerPool) (string, error) {
+	p := cr.Spec.ForProvider
+
+	t := p.Template.ForProvider.DeepCopy()
+	t.Labels = nil
+	t.PowerOn = false
+	t.PowerSchedule = nil
+	t.PowerOff = nil
+	t.ReadinessProbe = nil
+	t.ISO = nil
+	t.FirewallIDs, t.FirewallIDRefs, t.FirewallIDSelector = nil, nil, nil
+	t.NetworkIDs, t.NetworkIDRefs, t.NetworkIDSelector = nil, nil, nil
+	t.VolumeIDs, t.VolumeIDRefs, t.VolumeIDSelector = nil, nil, nil
+	t.PlacementGroupID, t.PlacementGroupIDRef, t.PlacementGroupIDSelector = nil, nil, nil
+
+	data, err := json.Marshal(struct {
+		NamePattern string                    `json:"namePattern"`
+		Spread      bool                      `json:"spread"`
+		Template    v1alpha1.ServerParameters `json:"template"`
+	}{p.NamePattern, p.Spread, *t})
+	if err != nil {
+		return "", errors.Wrap(err, "cannot hash server template")
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+
+	return strconv.FormatUint(uint64(h.Sum32()), 16), nil
+}
+
+func serverName(cr *v1alpha1.ServerPool, index int32) string {
+	pattern := cr.Spec.ForProvider.NamePattern
+	if pattern == "" {
+		pattern = "{pool}-{index}"
+	}
+
+	return strings.NewReplacer("{pool}", cr.GetName(), "{index}", strconv.Itoa(int(index))).Replace(pattern)
+}
+
+func placementGroupName(cr *v1alpha1.ServerPool) string {
+	return cr.GetName() + "-spread"
+}
+
+func serverIndex(s *v1alpha1.Server) (int32, bool) {
+	index, err := strconv.ParseInt(s.GetLabels()[LabelIndex], 10, 32)
+	if err != nil || index < 0 {
+		return 0, false
+	}
+
+	return int32(index), true
+}
+
+func ready(s *v1alpha1.Server) bool {
+	return s.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
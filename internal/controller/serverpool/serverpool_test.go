@@ -0,0 +1,472 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverpool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type poolModifier func(cr *v1alpha1.ServerPool)
+
+func pool(replicas int32, mods ...poolModifier) *v1alpha1.ServerPool {
+	cr := &v1alpha1.ServerPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers", UID: types.UID("pool-uid")},
+		Spec: v1alpha1.ServerPoolSpec{
+			ForProvider: v1alpha1.ServerPoolParameters{
+				Replicas:       replicas,
+				NamePattern:    "{pool}-{index}",
+				MaxUnavailable: 1,
+				Template: v1alpha1.ServerTemplate{
+					ForProvider: v1alpha1.ServerParameters{Image: "ubuntu-22.04", ServerType: "cx22"},
+				},
+			},
+		},
+	}
+	for _, m := range mods {
+		m(cr)
+	}
+	return cr
+}
+
+func withImage(image string) poolModifier {
+	return func(cr *v1alpha1.ServerPool) { cr.Spec.ForProvider.Template.ForProvider.Image = image }
+}
+
+func withLabels(labels apisv1alpha1.Labels) poolModifier {
+	return func(cr *v1alpha1.ServerPool) { cr.Spec.ForProvider.Template.ForProvider.Labels = labels }
+}
+
+func withFirewalls(ids ...int64) poolModifier {
+	return func(cr *v1alpha1.ServerPool) { cr.Spec.ForProvider.Template.ForProvider.FirewallIDs = ids }
+}
+
+func withFirewallRefs(names ...string) poolModifier {
+	return func(cr *v1alpha1.ServerPool) {
+		for _, name := range names {
+			cr.Spec.ForProvider.Template.ForProvider.FirewallIDRefs = append(cr.Spec.ForProvider.Template.ForProvider.FirewallIDRefs, xpv1.Reference{Name: name})
+		}
+	}
+}
+
+func withSpread() poolModifier {
+	return func(cr *v1alpha1.ServerPool) { cr.Spec.ForProvider.Spread = true }
+}
+
+func withDeletion() poolModifier {
+	return func(cr *v1alpha1.ServerPool) {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+	}
+}
+
+// member returns the server the pool wants at the index, as created from the
+// pool and made ready or not
+func member(cr *v1alpha1.ServerPool, index int32, isReady bool) *v1alpha1.Server {
+	hash, _ := templateHash(cr)
+	s := desiredServer(cr, index, hash)
+	if isReady {
+		s.SetConditions(xpv1.Available())
+	} else {
+		s.SetConditions(xpv1.Creating())
+	}
+	return s
+}
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.Server{}).
+		Build()
+}
+
+func TestObserve(t *testing.T) {
+	current := pool(2)
+	old := pool(2, withImage("ubuntu-20.04"))
+
+	// The server resolves its firewall references to IDs
+	referencing := pool(1, withFirewallRefs("web"))
+	resolved := member(referencing, 0, true)
+	resolved.Spec.ForProvider.FirewallIDs = []int64{1}
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.ServerPoolObservation
+		ready  corev1.ConditionStatus
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.ServerPool
+		servers []client.Object
+		want    want
+	}{
+		"Empty": {
+			reason: "A pool without its servers should need an update",
+			cr:     pool(2),
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true},
+				ready: corev1.ConditionFalse,
+			},
+		},
+		"Available": {
+			reason: "A pool whose servers are all ready should be available and up to date",
+			cr:     pool(2),
+			servers: []client.Object{
+				member(current, 0, true),
+				member(current, 1, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.ServerPoolObservation{
+					Replicas:          2,
+					ReadyReplicas:     2,
+					UpdatedReplicas:   2,
+					AvailableReplicas: 2,
+					Servers:           []string{"workers-0", "workers-1"},
+				},
+				ready: corev1.ConditionTrue,
+			},
+		},
+		"Outdated": {
+			reason: "A pool with servers created from an old template should need an update",
+			cr:     pool(2),
+			servers: []client.Object{
+				member(current, 0, true),
+				member(old, 1, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+				status: v1alpha1.ServerPoolObservation{
+					Replicas:          2,
+					ReadyReplicas:     2,
+					UpdatedReplicas:   1,
+					AvailableReplicas: 1,
+					Servers:           []string{"workers-0", "workers-1"},
+				},
+				ready: corev1.ConditionFalse,
+			},
+		},
+		"LabelsChanged": {
+			reason: "A pool whose template labels changed should need an update without counting its servers as outdated",
+			cr:     pool(1, withLabels(apisv1alpha1.Labels{"env": "test"})),
+			servers: []client.Object{
+				member(pool(1), 0, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+				status: v1alpha1.ServerPoolObservation{
+					Replicas:          1,
+					ReadyReplicas:     1,
+					UpdatedReplicas:   1,
+					AvailableReplicas: 1,
+					Servers:           []string{"workers-0"},
+				},
+				ready: corev1.ConditionTrue,
+			},
+		},
+		"FirewallsChanged": {
+			reason: "A pool whose template firewalls changed should need an update without counting its servers as outdated",
+			cr:     pool(1, withFirewalls(1)),
+			servers: []client.Object{
+				member(pool(1), 0, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+				status: v1alpha1.ServerPoolObservation{
+					Replicas:          1,
+					ReadyReplicas:     1,
+					UpdatedReplicas:   1,
+					AvailableReplicas: 1,
+					Servers:           []string{"workers-0"},
+				},
+				ready: corev1.ConditionTrue,
+			},
+		},
+		"ReferencesResolved": {
+			reason: "IDs which a server resolved from the template's references should not need an update",
+			cr:     referencing,
+			servers: []client.Object{
+				resolved,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.ServerPoolObservation{
+					Replicas:          1,
+					ReadyReplicas:     1,
+					UpdatedReplicas:   1,
+					AvailableReplicas: 1,
+					Servers:           []string{"workers-0"},
+				},
+				ready: corev1.ConditionTrue,
+			},
+		},
+		"Deleting": {
+			reason: "A deleted pool should exist until its servers are gone",
+			cr:     pool(1, withDeletion()),
+			servers: []client.Object{
+				member(current, 0, true),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"Deleted": {
+			reason: "A deleted pool without servers should no longer exist",
+			cr:     pool(1, withDeletion()),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: newClient(t, tc.servers...)}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.ready != "" && tc.cr.GetCondition(xpv1.TypeReady).Status != tc.want.ready {
+				t.Errorf("\n%s\ne.Observe(...): want Ready %s, got %s", tc.reason, tc.want.ready, tc.cr.GetCondition(xpv1.TypeReady).Status)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	current := pool(3)
+	old := pool(3, withImage("ubuntu-20.04"))
+
+	type want struct {
+		// Servers left after the update, and whether each was created from
+		// the current template
+		servers        map[string]bool
+		placementGroup bool
+		labels         apisv1alpha1.Labels
+		firewallIDs    []int64
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cr      *v1alpha1.ServerPool
+		servers []client.Object
+		want    want
+	}{
+		"ScaleUp": {
+			reason: "Missing servers should be created from the template",
+			cr:     pool(3),
+			servers: []client.Object{
+				member(current, 1, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-0": true, "workers-1": true, "workers-2": true},
+			},
+		},
+		"ScaleDown": {
+			reason: "The servers with the highest indexes should be deleted",
+			cr:     pool(1),
+			servers: []client.Object{
+				member(pool(1), 0, true),
+				member(pool(1), 1, true),
+				member(pool(1), 2, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-0": true},
+			},
+		},
+		"RollingReplacement": {
+			reason: "Only maxUnavailable ready servers should be replaced at once",
+			cr:     pool(3),
+			servers: []client.Object{
+				member(old, 0, true),
+				member(old, 1, true),
+				member(old, 2, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-1": false, "workers-2": false},
+			},
+		},
+		"ReplaceNotReady": {
+			reason: "Servers which are not ready should be replaced without waiting",
+			cr:     pool(3),
+			servers: []client.Object{
+				member(old, 0, false),
+				member(old, 1, true),
+				member(current, 2, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-1": false, "workers-2": true},
+			},
+		},
+		"WaitForUnavailable": {
+			reason: "No ready server should be replaced while another is unavailable",
+			cr:     pool(3),
+			servers: []client.Object{
+				member(current, 0, false),
+				member(old, 1, true),
+				member(old, 2, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-0": true, "workers-1": false, "workers-2": false},
+			},
+		},
+		"InPlace": {
+			reason: "Changes to the labels should be made to the existing servers",
+			cr:     pool(2, withLabels(apisv1alpha1.Labels{"env": "test"})),
+			servers: []client.Object{
+				member(pool(2), 0, true),
+				member(pool(2), 1, true),
+			},
+			want: want{
+				servers: map[string]bool{"workers-0": true, "workers-1": true},
+				labels:  apisv1alpha1.Labels{"env": "test"},
+			},
+		},
+		"InPlaceMemberships": {
+			reason: "Changes to the firewalls should be made to the existing servers rather than replacing them",
+			cr:     pool(2, withFirewalls(1, 2)),
+			servers: []client.Object{
+				member(pool(2, withFirewalls(1)), 0, true),
+				member(pool(2, withFirewalls(1)), 1, true),
+			},
+			want: want{
+				servers:     map[string]bool{"workers-0": true, "workers-1": true},
+				firewallIDs: []int64{1, 2},
+			},
+		},
+		"Spread": {
+			reason: "A spread pool should have a placement group which its servers reference",
+			cr:     pool(1, withSpread()),
+			want: want{
+				servers:        map[string]bool{"workers-0": true},
+				placementGroup: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := newClient(t, tc.servers...)
+			e := external{kube: kube}
+			if _, err := e.Update(context.Background(), tc.cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): %s", tc.reason, err)
+			}
+
+			hash, _ := templateHash(tc.cr)
+			servers, err := e.servers(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]bool{}
+			for _, s := range servers {
+				got[s.GetName()] = s.GetLabels()[LabelTemplateHash] == hash
+
+				if tc.want.labels != nil {
+					if diff := cmp.Diff(tc.want.labels, s.Spec.ForProvider.Labels); diff != "" {
+						t.Errorf("\n%s\ne.Update(...): -want labels, +got labels of %s:\n%s\n", tc.reason, s.GetName(), diff)
+					}
+				}
+				if tc.want.firewallIDs != nil {
+					if diff := cmp.Diff(tc.want.firewallIDs, s.Spec.ForProvider.FirewallIDs); diff != "" {
+						t.Errorf("\n%s\ne.Update(...): -want firewall IDs, +got firewall IDs of %s:\n%s\n", tc.reason, s.GetName(), diff)
+					}
+				}
+				if tc.want.placementGroup {
+					if diff := cmp.Diff(&xpv1.Reference{Name: "workers-spread"}, s.Spec.ForProvider.PlacementGroupIDRef); diff != "" {
+						t.Errorf("\n%s\ne.Update(...): -want placement group, +got placement group of %s:\n%s\n", tc.reason, s.GetName(), diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want.servers, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want servers, +got servers:\n%s\n", tc.reason, diff)
+			}
+
+			placementGroup, err := e.placementGroup(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want.placementGroup != (placementGroup != nil) {
+				t.Errorf("\n%s\ne.Update(...): want placement group %t, got %t", tc.reason, tc.want.placementGroup, placementGroup != nil)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cr := pool(2, withSpread(), withDeletion())
+
+	kube := newClient(t, member(cr, 0, true), member(cr, 1, true))
+	e := external{kube: kube}
+
+	if _, err := e.Update(context.Background(), pool(2, withSpread())); err != nil {
+		t.Fatalf("e.Update(...): %s", err)
+	}
+
+	// The servers are deleted first, then the placement group
+	for i, want := range []bool{true, false} {
+		if err := e.Delete(context.Background(), cr); err != nil {
+			t.Fatalf("e.Delete(...): %s", err)
+		}
+
+		got, err := e.Observe(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("e.Observe(...): %s", err)
+		}
+		if got.ResourceExists != want {
+			t.Errorf("e.Delete(...) call %d: want exists %t, got %t", i+1, want, got.ResourceExists)
+		}
+	}
+}
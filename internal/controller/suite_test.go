@@ -0,0 +1,830 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	"github.com/mrsimonemms/provider-hetzner/apis"
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1beta1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+	hetzner "github.com/mrsimonemms/provider-hetzner/internal/controller"
+	"github.com/mrsimonemms/provider-hetzner/internal/controller/serverpool"
+	"github.com/mrsimonemms/provider-hetzner/internal/webhook"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
+)
+
+const (
+	namespace = "default"
+	interval  = 50 * time.Millisecond
+
+	// timeout outlasts the managed reconciler's 30 second creation grace
+	// period, during which a deleted external resource isn't trusted to be
+	// gone, plus the requeue backoff accumulated while waiting it out
+	timeout = 2 * time.Minute
+)
+
+// TestReconcile runs every controller against a real API server started by
+// envtest and the fake Hetzner API. It's skipped unless KUBEBUILDER_ASSETS
+// points at the envtest control plane binaries, which make test-envtest sets.
+func TestReconcile(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set; run make test-envtest to install the envtest binaries and run this suite")
+	}
+
+	api := fake.NewAPI()
+	t.Cleanup(api.Close)
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// The scheme lets envtest point the CRDs' conversion webhooks, as well
+	// as the validating webhooks, at the manager's webhook server
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		CRDInstallOptions:     envtest.CRDInstallOptions{Scheme: scheme},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "package", "webhookconfigurations")},
+		},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("cannot start envtest: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("cannot stop envtest: %s", err)
+		}
+	})
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Host:    env.WebhookInstallOptions.LocalServingHost,
+			Port:    env.WebhookInstallOptions.LocalServingPort,
+			CertDir: env.WebhookInstallOptions.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("cannot create manager: %s", err)
+	}
+
+	if err := webhook.Setup(mgr); err != nil {
+		t.Fatalf("cannot set up webhooks: %s", err)
+	}
+
+	err = hetzner.Setup(mgr, controller.Options{
+		Logger:                  logging.NewNopLogger(),
+		MaxConcurrentReconciles: 1,
+		PollInterval:            time.Second,
+		GlobalRateLimiter:       ratelimiter.NewGlobal(100),
+		Features:                &feature.Flags{},
+	}, api.ClientOptions()...)
+	if err != nil {
+		t.Fatalf("cannot set up controllers: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- mgr.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("manager stopped with error: %s", err)
+		}
+	})
+
+	// Read straight from the API server, as the manager's cache can lag
+	// behind the objects the test has just created
+	kube, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+
+	createProviderConfig(ctx, t, kube)
+
+	cases := map[string]lifecycle{
+		"Firewall":       firewallLifecycle(api),
+		"Network":        networkLifecycle(api),
+		"PlacementGroup": placementGroupLifecycle(api),
+		"Server":         serverLifecycle(api),
+		"Volume":         volumeLifecycle(api),
+	}
+
+	for name, tc := range cases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key := client.ObjectKeyFromObject(tc.mg)
+
+			if err := kube.Create(ctx, tc.mg); err != nil {
+				t.Fatalf("cannot create %s: %s", name, err)
+			}
+
+			eventually(t, "resource is ready", func() (bool, error) {
+				if err := kube.Get(ctx, key, tc.mg); err != nil {
+					return false, err
+				}
+				return tc.mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+			})
+
+			id := tc.id()
+			if _, ok := tc.labels(id); !ok {
+				t.Fatalf("%s %d does not exist in the Hetzner API", name, id)
+			}
+
+			eventually(t, "ProviderConfigUsage is tracked", func() (bool, error) {
+				return providerConfigUsed(ctx, kube, tc.mg)
+			})
+
+			if tc.mg.GetWriteConnectionSecretToReference() != nil {
+				eventually(t, "connection secret is published", func() (bool, error) {
+					return connectionSecretPublished(ctx, kube, tc.mg)
+				})
+			}
+
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if err := kube.Get(ctx, key, tc.mg); err != nil {
+					return err
+				}
+				tc.setLabels(apisv1alpha1.Labels{"env": "test"})
+				return kube.Update(ctx, tc.mg)
+			})
+			if err != nil {
+				t.Fatalf("cannot update %s: %s", name, err)
+			}
+
+			eventually(t, "labels are updated", func() (bool, error) {
+				labels, _ := tc.labels(id)
+				return labels["env"] == "test", nil
+			})
+
+			if err := kube.Delete(ctx, tc.mg); err != nil {
+				t.Fatalf("cannot delete %s: %s", name, err)
+			}
+
+			eventually(t, "resource is deleted", func() (bool, error) {
+				err := kube.Get(ctx, key, tc.mg)
+				if kerrors.IsNotFound(err) {
+					_, ok := tc.labels(id)
+					return !ok, nil
+				}
+				return false, err
+			})
+		})
+	}
+
+	t.Run("VolumeReplacement", func(t *testing.T) {
+		t.Parallel()
+
+		cr := &v1alpha1.Volume{
+			ObjectMeta: metav1.ObjectMeta{Name: "volume-replacement"},
+			Spec: v1alpha1.VolumeSpec{
+				ForProvider: v1alpha1.VolumeParameters{
+					Size:     10,
+					Format:   "ext4",
+					Location: hcloudsdk.Ptr("fsn1"),
+				},
+			},
+		}
+		key := client.ObjectKeyFromObject(cr)
+
+		if err := kube.Create(ctx, cr); err != nil {
+			t.Fatalf("cannot create volume: %s", err)
+		}
+
+		eventually(t, "volume is ready", func() (bool, error) {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return false, err
+			}
+			return cr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+		})
+
+		id := cr.Status.AtProvider.ID
+
+		cr.Spec.ForProvider.Format = "xfs"
+		if err := kube.Update(ctx, cr); !kerrors.IsInvalid(err) {
+			t.Fatalf("changing a create-only field without a replacement policy: want invalid error, got %v", err)
+		}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return err
+			}
+			cr.Spec.ReplacementPolicy = apisv1alpha1.ReplacementPolicyReplace
+			cr.Spec.ForProvider.Format = "xfs"
+			return kube.Update(ctx, cr)
+		})
+		if err != nil {
+			t.Fatalf("cannot update volume: %s", err)
+		}
+
+		eventually(t, "volume is replaced", func() (bool, error) {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return false, err
+			}
+			if _, ok := api.Volume(id); ok {
+				return false, nil
+			}
+			volume, ok := api.Volume(cr.Status.AtProvider.ID)
+			return ok && volume.Format != nil && *volume.Format == "xfs", nil
+		})
+
+		if err := kube.Delete(ctx, cr); err != nil {
+			t.Fatalf("cannot delete volume: %s", err)
+		}
+	})
+
+	t.Run("V1beta1", func(t *testing.T) {
+		t.Parallel()
+
+		cr := &v1beta1.PlacementGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement-group-v1beta1"},
+			Spec: v1beta1.PlacementGroupSpec{
+				ForProvider: v1beta1.PlacementGroupParameters{
+					Type: hcloudsdk.PlacementGroupTypeSpread,
+				},
+			},
+		}
+		key := client.ObjectKeyFromObject(cr)
+
+		if err := kube.Create(ctx, cr); err != nil {
+			t.Fatalf("cannot create v1beta1 placement group: %s", err)
+		}
+
+		eventually(t, "v1beta1 placement group is ready", func() (bool, error) {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return false, err
+			}
+			return cr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+		})
+
+		id := cr.Status.AtProvider.ID
+		if _, ok := api.PlacementGroup(id); !ok {
+			t.Fatalf("placement group %d does not exist in the Hetzner API", id)
+		}
+
+		// Updating through v1beta1 must keep the parameters the controller
+		// recorded in the v1alpha1 status
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return err
+			}
+			cr.Spec.ForProvider.Labels = apisv1alpha1.Labels{"env": "test"}
+			return kube.Update(ctx, cr)
+		})
+		if err != nil {
+			t.Fatalf("cannot update v1beta1 placement group: %s", err)
+		}
+
+		hub := &v1alpha1.PlacementGroup{}
+		if err := kube.Get(ctx, key, hub); err != nil {
+			t.Fatalf("cannot get v1alpha1 placement group: %s", err)
+		}
+		if hub.Status.AtProvider.PlacementGroupParameters == nil {
+			t.Fatal("v1alpha1 status parameters were lost when updating through v1beta1")
+		}
+		if _, ok := hub.GetAnnotations()[v1beta1.ConversionDataAnnotation]; ok {
+			t.Fatal("v1alpha1 placement group has the v1beta1 conversion data annotation")
+		}
+
+		eventually(t, "labels are updated", func() (bool, error) {
+			placementGroup, _ := api.PlacementGroup(id)
+			return placementGroup.Labels["env"] == "test", nil
+		})
+
+		if err := kube.Delete(ctx, cr); err != nil {
+			t.Fatalf("cannot delete v1beta1 placement group: %s", err)
+		}
+	})
+
+	t.Run("ServerPool", func(t *testing.T) {
+		t.Parallel()
+
+		cr := &v1alpha1.ServerPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-pool"},
+			Spec: v1alpha1.ServerPoolSpec{
+				ForProvider: v1alpha1.ServerPoolParameters{
+					Replicas:       2,
+					MaxUnavailable: 1,
+					Template: v1alpha1.ServerTemplate{
+						ForProvider: v1alpha1.ServerParameters{
+							Image:            "ubuntu-22.04",
+							ServerType:       "cx22",
+							Location:         hcloudsdk.Ptr("fsn1"),
+							Architecture:     hcloudsdk.ArchitectureX86,
+							EnableIPv4:       true,
+							EnableIPv6:       true,
+							PowerOn:          true,
+							StartAfterCreate: true,
+						},
+					},
+				},
+			},
+		}
+		key := client.ObjectKeyFromObject(cr)
+
+		// servers returns the pool's servers that aren't being deleted
+		servers := func() ([]v1alpha1.Server, error) {
+			l := &v1alpha1.ServerList{}
+			if err := kube.List(ctx, l, client.MatchingLabels{serverpool.LabelPool: cr.GetName()}); err != nil {
+				return nil, err
+			}
+			live := make([]v1alpha1.Server, 0, len(l.Items))
+			for _, s := range l.Items {
+				if s.GetDeletionTimestamp() == nil {
+					live = append(live, s)
+				}
+			}
+			return live, nil
+		}
+
+		// available waits until the pool has every replica available, and
+		// returns the Hetzner IDs of its servers
+		available := func(what string, replicas int32) map[int64]bool {
+			t.Helper()
+
+			ids := map[int64]bool{}
+			eventually(t, what, func() (bool, error) {
+				if err := kube.Get(ctx, key, cr); err != nil {
+					return false, err
+				}
+				o := cr.Status.AtProvider
+				if o.Replicas != replicas || o.AvailableReplicas != replicas || cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+					return false, nil
+				}
+
+				live, err := servers()
+				if err != nil || int32(len(live)) != replicas {
+					return false, err
+				}
+				clear(ids)
+				for _, s := range live {
+					if _, ok := api.Server(s.Status.AtProvider.ID); !ok {
+						return false, nil
+					}
+					ids[s.Status.AtProvider.ID] = true
+				}
+				return true, nil
+			})
+
+			return ids
+		}
+
+		update := func(what string, fn func(p *v1alpha1.ServerPoolParameters)) {
+			t.Helper()
+
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if err := kube.Get(ctx, key, cr); err != nil {
+					return err
+				}
+				fn(&cr.Spec.ForProvider)
+				return kube.Update(ctx, cr)
+			})
+			if err != nil {
+				t.Fatalf("cannot %s: %s", what, err)
+			}
+		}
+
+		if err := kube.Create(ctx, cr); err != nil {
+			t.Fatalf("cannot create server pool: %s", err)
+		}
+
+		created := available("server pool is available", 2)
+
+		update("scale server pool", func(p *v1alpha1.ServerPoolParameters) { p.Replicas = 3 })
+		scaled := available("server pool is scaled up", 3)
+		for id := range created {
+			if !scaled[id] {
+				t.Fatalf("server %d was replaced when the pool was scaled up", id)
+			}
+		}
+
+		// Labels are updated on the existing servers, so neither the
+		// servers nor their template hash change
+		hash := func() (map[string]bool, error) {
+			live, err := servers()
+			hashes := map[string]bool{}
+			for _, s := range live {
+				hashes[s.GetLabels()[serverpool.LabelTemplateHash]] = true
+			}
+			return hashes, err
+		}
+		before, err := hash()
+		if err != nil {
+			t.Fatalf("cannot list servers: %s", err)
+		}
+
+		update("update server pool labels", func(p *v1alpha1.ServerPoolParameters) {
+			p.Template.ForProvider.Labels = apisv1alpha1.Labels{"env": "test"}
+		})
+		eventually(t, "server labels are updated in place", func() (bool, error) {
+			for id := range scaled {
+				server, ok := api.Server(id)
+				if !ok {
+					return false, fmt.Errorf("server %d was replaced by a label change", id)
+				}
+				if server.Labels["env"] != "test" {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if after, err := hash(); err != nil || !cmp.Equal(before, after) {
+			t.Fatalf("template hash changed from %v to %v (%v) by a label change", before, after, err)
+		}
+
+		// Changing the image replaces the servers, no more than
+		// maxUnavailable at a time
+		update("change server pool image", func(p *v1alpha1.ServerPoolParameters) { p.Template.ForProvider.Image = "debian-12" })
+		eventually(t, "servers are replaced", func() (bool, error) {
+			live, err := servers()
+			if err != nil {
+				return false, err
+			}
+
+			ready, replaced := 0, 0
+			for _, s := range live {
+				if s.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+					ready++
+				}
+				if id := s.Status.AtProvider.ID; id != 0 && !scaled[id] {
+					replaced++
+				}
+			}
+			if ready < 3-1 {
+				return false, fmt.Errorf("%d of 3 servers are ready, more than maxUnavailable are unavailable", ready)
+			}
+
+			for id := range scaled {
+				if _, ok := api.Server(id); ok {
+					return false, nil
+				}
+			}
+			return replaced == 3, nil
+		})
+		replaced := available("replaced server pool is available", 3)
+		for id := range replaced {
+			server, _ := api.Server(id)
+			if server.Image == nil || server.Image.Name == nil || *server.Image.Name != "debian-12" {
+				t.Fatalf("server %d was not replaced with the new image", id)
+			}
+		}
+
+		if err := kube.Delete(ctx, cr); err != nil {
+			t.Fatalf("cannot delete server pool: %s", err)
+		}
+
+		eventually(t, "server pool is deleted", func() (bool, error) {
+			err := kube.Get(ctx, key, cr)
+			if !kerrors.IsNotFound(err) {
+				return false, client.IgnoreNotFound(err)
+			}
+			for id := range replaced {
+				if _, ok := api.Server(id); ok {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		t.Parallel()
+
+		cr := &v1alpha1.PlacementGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement-group-project"},
+			Spec: v1alpha1.PlacementGroupSpec{
+				Project: hcloudsdk.Ptr("staging"),
+				ForProvider: v1alpha1.PlacementGroupParameters{
+					Type: hcloudsdk.PlacementGroupTypeSpread,
+				},
+			},
+		}
+		key := client.ObjectKeyFromObject(cr)
+
+		if err := kube.Create(ctx, cr); err != nil {
+			t.Fatalf("cannot create placement group: %s", err)
+		}
+
+		eventually(t, "placement group is ready", func() (bool, error) {
+			if err := kube.Get(ctx, key, cr); err != nil {
+				return false, err
+			}
+			return cr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+		})
+
+		eventually(t, "ProviderConfigUsage is tracked", func() (bool, error) {
+			return providerConfigUsed(ctx, kube, cr)
+		})
+
+		missing := &v1alpha1.PlacementGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement-group-missing-project"},
+			Spec: v1alpha1.PlacementGroupSpec{
+				Project: hcloudsdk.Ptr("production"),
+				ForProvider: v1alpha1.PlacementGroupParameters{
+					Type: hcloudsdk.PlacementGroupTypeSpread,
+				},
+			},
+		}
+		if err := kube.Create(ctx, missing); err != nil {
+			t.Fatalf("cannot create placement group: %s", err)
+		}
+
+		eventually(t, "missing project fails to sync", func() (bool, error) {
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(missing), missing); err != nil {
+				return false, err
+			}
+			synced := missing.GetCondition(xpv1.TypeSynced)
+			return synced.Status == corev1.ConditionFalse && strings.Contains(synced.Message, `project "production" not found`), nil
+		})
+
+		for _, mg := range []client.Object{cr, missing} {
+			if err := kube.Delete(ctx, mg); err != nil {
+				t.Fatalf("cannot delete placement group: %s", err)
+			}
+		}
+	})
+}
+
+// A lifecycle is a managed resource and accessors for its state in both
+// Kubernetes and the fake Hetzner API
+type lifecycle struct {
+	mg        resource.Managed
+	id        func() int64
+	labels    func(id int64) (map[string]string, bool)
+	setLabels func(labels apisv1alpha1.Labels)
+}
+
+func firewallLifecycle(api *fake.API) (l lifecycle) {
+	cr := &v1alpha1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "firewall"},
+		Spec: v1alpha1.FirewallSpec{
+			ForProvider: v1alpha1.FirewallParameters{
+				Rules: []v1alpha1.FirewallRules{
+					{
+						Direction: hcloudsdk.FirewallRuleDirectionIn,
+						Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+						TargetIPs: []string{"0.0.0.0/0"},
+						Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(22)},
+					},
+				},
+			},
+		},
+	}
+	l.mg = cr
+	l.id = func() int64 { return cr.Status.AtProvider.ID }
+	l.labels = func(id int64) (map[string]string, bool) {
+		firewall, ok := api.Firewall(id)
+		return firewall.Labels, ok
+	}
+	l.setLabels = func(labels apisv1alpha1.Labels) { cr.Spec.ForProvider.Labels = labels }
+	return l
+}
+
+func networkLifecycle(api *fake.API) (l lifecycle) {
+	cr := &v1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: "network"},
+		Spec: v1alpha1.NetworkSpec{
+			ForProvider: v1alpha1.NetworkParameters{
+				IPRange: "10.0.0.0/16",
+				Subnets: []v1alpha1.NetworkSubnet{
+					{
+						Type:        hcloudsdk.NetworkSubnetTypeCloud,
+						IPRange:     "10.0.1.0/24",
+						NetworkZone: hcloudsdk.NetworkZoneEUCentral,
+					},
+				},
+			},
+		},
+	}
+	l.mg = cr
+	l.id = func() int64 { return cr.Status.AtProvider.ID }
+	l.labels = func(id int64) (map[string]string, bool) {
+		network, ok := api.Network(id)
+		return network.Labels, ok
+	}
+	l.setLabels = func(labels apisv1alpha1.Labels) { cr.Spec.ForProvider.Labels = labels }
+	return l
+}
+
+func placementGroupLifecycle(api *fake.API) (l lifecycle) {
+	cr := &v1alpha1.PlacementGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "placement-group"},
+		Spec: v1alpha1.PlacementGroupSpec{
+			ForProvider: v1alpha1.PlacementGroupParameters{
+				Type: hcloudsdk.PlacementGroupTypeSpread,
+			},
+		},
+	}
+	l.mg = cr
+	l.id = func() int64 { return cr.Status.AtProvider.ID }
+	l.labels = func(id int64) (map[string]string, bool) {
+		placementGroup, ok := api.PlacementGroup(id)
+		return placementGroup.Labels, ok
+	}
+	l.setLabels = func(labels apisv1alpha1.Labels) { cr.Spec.ForProvider.Labels = labels }
+	return l
+}
+
+func serverLifecycle(api *fake.API) (l lifecycle) {
+	cr := &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "server"},
+		Spec: v1alpha1.ServerSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				WriteConnectionSecretToReference: &xpv1.SecretReference{
+					Name:      "server-connection",
+					Namespace: namespace,
+				},
+			},
+			ForProvider: v1alpha1.ServerParameters{
+				Image:            "ubuntu-22.04",
+				ServerType:       "cx22",
+				Location:         hcloudsdk.Ptr("fsn1"),
+				Architecture:     hcloudsdk.ArchitectureX86,
+				EnableIPv4:       true,
+				EnableIPv6:       true,
+				PowerOn:          true,
+				StartAfterCreate: true,
+			},
+		},
+	}
+	l.mg = cr
+	l.id = func() int64 { return cr.Status.AtProvider.ID }
+	l.labels = func(id int64) (map[string]string, bool) {
+		server, ok := api.Server(id)
+		return server.Labels, ok
+	}
+	l.setLabels = func(labels apisv1alpha1.Labels) { cr.Spec.ForProvider.Labels = labels }
+	return l
+}
+
+func volumeLifecycle(api *fake.API) (l lifecycle) {
+	cr := &v1alpha1.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: "volume"},
+		Spec: v1alpha1.VolumeSpec{
+			ForProvider: v1alpha1.VolumeParameters{
+				Size:     10,
+				Format:   "ext4",
+				Location: hcloudsdk.Ptr("fsn1"),
+			},
+		},
+	}
+	l.mg = cr
+	l.id = func() int64 { return cr.Status.AtProvider.ID }
+	l.labels = func(id int64) (map[string]string, bool) {
+		volume, ok := api.Volume(id)
+		return volume.Labels, ok
+	}
+	l.setLabels = func(labels apisv1alpha1.Labels) { cr.Spec.ForProvider.Labels = labels }
+	return l
+}
+
+func createProviderConfig(ctx context.Context, t *testing.T, kube client.Client) {
+	t.Helper()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hcloud", Namespace: namespace},
+		StringData: map[string]string{"token": "fake-token", "staging-token": "fake-staging-token"},
+	}
+	if err := kube.Create(ctx, secret); err != nil {
+		t.Fatalf("cannot create credentials secret: %s", err)
+	}
+
+	credentials := func(key string) apisv1alpha1.ProviderCredentials {
+		return apisv1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{
+						Name:      secret.Name,
+						Namespace: namespace,
+					},
+					Key: key,
+				},
+			},
+		}
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1alpha1.ProviderConfigSpec{
+			Credentials: credentials("token"),
+			Projects: []apisv1alpha1.ProjectCredentials{
+				{Name: "staging", Credentials: credentials("staging-token")},
+			},
+		},
+	}
+	if err := kube.Create(ctx, pc); err != nil {
+		t.Fatalf("cannot create ProviderConfig: %s", err)
+	}
+}
+
+func providerConfigUsed(ctx context.Context, kube client.Client, mg resource.Managed) (bool, error) {
+	usages := &apisv1alpha1.ProviderConfigUsageList{}
+	if err := kube.List(ctx, usages); err != nil {
+		return false, err
+	}
+
+	for _, u := range usages.Items {
+		if u.ResourceReference.Name == mg.GetName() && u.ProviderConfigReference.Name == "default" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func connectionSecretPublished(ctx context.Context, kube client.Client, mg resource.Managed) (bool, error) {
+	ref := mg.GetWriteConnectionSecretToReference()
+
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	for _, key := range []string{
+		xpv1.ResourceCredentialsSecretEndpointKey,
+		xpv1.ResourceCredentialsSecretUserKey,
+		xpv1.ResourceCredentialsSecretPasswordKey,
+	} {
+		if len(secret.Data[key]) == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// eventually polls fn until it returns true, failing the test if it errors or
+// times out
+func eventually(t *testing.T, what string, fn func() (bool, error)) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := fn()
+		if err != nil {
+			t.Fatalf("waiting until %s: %s", what, err)
+		}
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %s", what)
+		}
+		time.Sleep(interval)
+	}
+}
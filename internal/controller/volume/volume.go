@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +39,7 @@ import (
 	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
 	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/internal/features"
+	"github.com/mrsimonemms/provider-hetzner/internal/tracing"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
 )
 
@@ -48,10 +50,13 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	reasonReplaced event.Reason = "ReplacedExternalResource"
 )
 
 // Setup adds a controller that reconciles Volume managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// The options are given to every Hetzner client it creates.
+func Setup(mgr ctrl.Manager, o controller.Options, opts ...hcloud.Option) error {
 	name := managed.ControllerName(v1alpha1.VolumeGroupKind)
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
@@ -65,9 +70,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newServiceFn: hcloud.NewClient,
+			clientOpts:   opts,
+			recorder:     event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithConnectionPublishers(cps...))
 
@@ -84,7 +92,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds string) (*hcloud.Client, error)
+	newServiceFn func(creds string, opts ...hcloud.Option) (*hcloud.Client, error)
+	clientOpts   []hcloud.Option
+	recorder     event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -107,28 +117,40 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
+	cd, err := pc.GetCredentials(cr.Spec.Project)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(string(data))
+	svc, err := c.newServiceFn(string(data), append([]hcloud.Option{
+		hcloud.WithDefaultLabels(pc.Spec.Labels.DefaultLabels(mg)),
+		hcloud.WithLabelMode(pc.Spec.Labels.GetMode()),
+		hcloud.WithProviderConfig(pc.Name, cr.Spec.Project),
+	}, c.clientOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{
-		kube:   c.kube,
-		hcloud: svc,
-	}, nil
+	return tracing.NewExternalClient(v1alpha1.VolumeKind, func(mg resource.Managed) int64 {
+		return mg.(*v1alpha1.Volume).Status.AtProvider.ID
+	}, &external{
+		kube:     c.kube,
+		hcloud:   svc,
+		recorder: c.recorder,
+	}), nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	kube   client.Client
-	hcloud *hcloud.Client
+	kube     client.Client
+	hcloud   *hcloud.Client
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -137,7 +159,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotVolume)
 	}
 
-	volume, _, err := c.hcloud.Client.Volume.GetByID(ctx, cr.Status.AtProvider.ID)
+	volume, _, err := c.hcloud.Volume.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, err
 	}
@@ -145,6 +167,19 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
+	if cr.NeedsReplacement() {
+		// The volume is replaced by Update, so that it is never deleted
+		// just by being observed
+		cr.SetConditions(apisv1alpha1.ReplacementPending())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+	}
+	if cr.GetCondition(apisv1alpha1.TypeReplacementPending).Status == corev1.ConditionTrue {
+		cr.SetConditions(apisv1alpha1.ReplacementNotPending())
+	}
+
+	cr.Status.AtProvider.Status = volume.Status
+	cr.Status.AtProvider.LinuxDevice = volume.LinuxDevice
+
 	switch volume.Status {
 	case hcloudsdk.VolumeStatusAvailable:
 		cr.SetConditions(xpv1.Available())
@@ -154,7 +189,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: cr.IsUpToDate(),
+		ResourceUpToDate: cr.IsUpToDate() && c.hcloud.LabelsUpToDate(volume.Labels, cr.Spec.ForProvider.Labels.Map()),
 	}, nil
 }
 
@@ -168,7 +203,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	var server *hcloudsdk.Server
 
 	if id := cr.Spec.ForProvider.Location; id != nil {
-		l, _, err := c.hcloud.Client.Location.GetByName(ctx, *id)
+		l, _, err := c.hcloud.Location.GetByName(ctx, *id)
 		if err != nil {
 			return managed.ExternalCreation{}, errors.Wrap(err, "failed to get location")
 		}
@@ -179,7 +214,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	if id := cr.Spec.ForProvider.ServerID; id != nil {
-		s, _, err := c.hcloud.Client.Server.GetByID(ctx, *id)
+		s, _, err := c.hcloud.Server.GetByID(ctx, *id)
 		if err != nil {
 			return managed.ExternalCreation{}, errors.Wrap(err, "failed to get server")
 		}
@@ -189,10 +224,15 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		server = s
 	}
 
-	volume, _, err := c.hcloud.Client.Volume.Create(ctx, hcloudsdk.VolumeCreateOpts{
+	labels, err := c.hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels.Map())
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	volume, _, err := c.hcloud.Volume.Create(ctx, hcloudsdk.VolumeCreateOpts{
 		Automount: &cr.Spec.ForProvider.Automount,
 		Format:    &cr.Spec.ForProvider.Format,
-		Labels:    hcloud.ApplyDefaultLabels(cr.Spec.ForProvider.Labels),
+		Labels:    labels,
 		Location:  location,
 		Name:      cr.ObjectMeta.Name,
 		Server:    server,
@@ -217,7 +257,7 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotVolume)
 	}
 
-	volume, _, err := c.hcloud.Client.Volume.GetByID(ctx, cr.Status.AtProvider.ID)
+	volume, _, err := c.hcloud.Volume.GetByID(ctx, cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to get volume")
 	}
@@ -225,11 +265,20 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, fmt.Errorf("unknown volume")
 	}
 
+	if cr.NeedsReplacement() {
+		return managed.ExternalUpdate{}, c.replace(ctx, cr)
+	}
+
 	current := *cr.Status.AtProvider.VolumeParameters // What we have
 	target := cr.Spec.ForProvider                     // What we want
 
-	if _, _, err := c.hcloud.Client.Volume.Update(ctx, volume, hcloudsdk.VolumeUpdateOpts{
-		Labels: hcloud.ApplyDefaultLabels(target.Labels),
+	labels, err := c.hcloud.UpdateLabels(volume.Labels, target.Labels.Map())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if _, _, err := c.hcloud.Volume.Update(ctx, volume, hcloudsdk.VolumeUpdateOpts{
+		Labels: labels,
 	}); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "failed to update server")
 	}
@@ -266,16 +315,18 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	volume := hcloudsdk.Volume{
 		ID: cr.Status.AtProvider.ID,
-		Server: &hcloudsdk.Server{
-			ID: *cr.Status.AtProvider.VolumeParameters.ServerID,
-		},
+	}
+	if params := cr.Status.AtProvider.VolumeParameters; params != nil && params.ServerID != nil {
+		volume.Server = &hcloudsdk.Server{
+			ID: *params.ServerID,
+		}
 	}
 
 	if err := c.updateServerAttachment(ctx, &volume); err != nil {
 		return errors.Wrap(err, "failed to detach volumes before delete")
 	}
 
-	_, err := c.hcloud.Client.Volume.Delete(ctx, &volume)
+	_, err := c.hcloud.Volume.Delete(ctx, &volume)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete volume")
 	}
@@ -283,8 +334,27 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	return nil
 }
 
+// replace deletes a volume whose create-only parameters have changed, so that
+// it is created again with the new parameters
+func (c *external) replace(ctx context.Context, cr *v1alpha1.Volume) error {
+	id := cr.Status.AtProvider.ID
+	if err := c.Delete(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to delete volume for replacement")
+	}
+
+	c.recorder.Event(cr, event.Normal(reasonReplaced, fmt.Sprintf("Deleted volume %d to replace it as a create-only field changed", id)))
+
+	cr.Status.AtProvider = v1alpha1.VolumeObservation{}
+	cr.SetConditions(xpv1.Creating(), apisv1alpha1.ReplacementNotPending())
+	if err := c.kube.Status().Update(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to save status")
+	}
+
+	return nil
+}
+
 func (c *external) resize(ctx context.Context, volume *hcloudsdk.Volume, size int) error {
-	action, _, err := c.hcloud.Client.Volume.Resize(ctx, volume, size)
+	action, _, err := c.hcloud.Volume.Resize(ctx, volume, size)
 	if err != nil {
 		return errors.Wrap(err, "failed to trigger resize volume")
 	}
@@ -298,7 +368,7 @@ func (c *external) resize(ctx context.Context, volume *hcloudsdk.Volume, size in
 
 func (c *external) updateServerAttachment(ctx context.Context, volume *hcloudsdk.Volume, params ...v1alpha1.VolumeParameters) error {
 	if volume.Server != nil {
-		action, _, err := c.hcloud.Client.Volume.Detach(ctx, volume)
+		action, _, err := c.hcloud.Volume.Detach(ctx, volume)
 		if err != nil {
 			return errors.Wrap(err, "failed to trigger detach volume")
 		}
@@ -309,7 +379,7 @@ func (c *external) updateServerAttachment(ctx context.Context, volume *hcloudsdk
 
 	for _, p := range params {
 		if p.ServerID != nil {
-			action, _, err := c.hcloud.Client.Volume.AttachWithOpts(ctx, volume, hcloudsdk.VolumeAttachOpts{
+			action, _, err := c.hcloud.Volume.AttachWithOpts(ctx, volume, hcloudsdk.VolumeAttachOpts{
 				Server: &hcloudsdk.Server{
 					ID: *p.ServerID,
 				},
@@ -18,11 +18,17 @@ package volume
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud/fake"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,7 +43,39 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func volume(id int64) *v1alpha1.Volume {
+	return &v1alpha1.Volume{
+		Spec: v1alpha1.VolumeSpec{
+			ForProvider: v1alpha1.VolumeParameters{Size: 10},
+		},
+		Status: v1alpha1.VolumeStatus{
+			AtProvider: v1alpha1.VolumeObservation{
+				ID:               id,
+				VolumeParameters: &v1alpha1.VolumeParameters{Size: 10},
+			},
+		},
+	}
+}
+
 func TestObserve(t *testing.T) {
+	api := fake.NewAPI()
+	defer api.Close()
+
+	labels := fake.ProviderLabels()
+
+	running := api.AddVolume(schema.Volume{Name: "running", Labels: labels})
+	drifted := api.AddVolume(schema.Volume{Name: "drifted"})
+	failing := api.AddVolume(schema.Volume{Name: "failing"})
+
+	forbidden := api.Forbid(fmt.Sprintf("/volumes/%d", failing.ID))
+
+	changed := volume(running.ID)
+	changed.Spec.ForProvider.Size = 20
+
+	replaced := volume(running.ID)
+	replaced.Spec.ReplacementPolicy = apisv1alpha1.ReplacementPolicyReplace
+	replaced.Spec.ForProvider.Format = "ext4"
+
 	type fields struct {
 		hcloud *hcloud.Client
 	}
@@ -58,7 +96,73 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A volume which does not exist should be reported as such",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(999),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A volume matching the desired state should be up to date",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(running.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LabelsDrifted": {
+			reason: "A volume missing the provider labels should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(drifted.ID),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"SizeIncreased": {
+			reason: "An increase in the desired size should need an update",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  changed,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"ReplacementPending": {
+			reason: "A change to a create-only field should need an update rather than deleting the volume",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  replaced,
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"GetFailed": {
+			reason: "Errors from the API should be returned",
+			fields: fields{hcloud: api.Client()},
+			args: args{
+				ctx: context.Background(),
+				mg:  volume(failing.ID),
+			},
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: false},
+				err: forbidden,
+			},
+		},
 	}
 
 	for name, tc := range cases {
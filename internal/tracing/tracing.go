@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures OpenTelemetry tracing for the provider and
+// traces the managed resource external clients.
+package tracing
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+)
+
+const (
+	serviceName = "provider-hetzner"
+	tracerName  = "github.com/mrsimonemms/provider-hetzner/internal/tracing"
+)
+
+// Attribute keys set on the external client spans
+const (
+	KindKey = attribute.Key("crossplane.kind")
+	NameKey = attribute.Key("crossplane.name")
+)
+
+// Options configure the OTLP exporter
+type Options struct {
+	// Endpoint of the OTLP gRPC collector, such as "otel-collector:4317".
+	// Tracing is disabled if empty.
+	Endpoint string
+
+	// Insecure disables TLS to the collector
+	Insecure bool
+
+	// SampleRatio is the fraction of new traces to record
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider, exporting spans over OTLP. The
+// returned function flushes and stops the exporter. Nothing is installed if
+// no endpoint is given, leaving the no-op tracer in place.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create OTLP trace exporter")
+	}
+
+	res, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build trace resource")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
+
+// NewExternalClient wraps an ExternalClient so each of its methods runs in
+// a span tagged with the resource's kind, name and Hetzner ID. The Hetzner
+// requests made by the method become child spans.
+func NewExternalClient(kind string, id func(resource.Managed) int64, e managed.ExternalClient) managed.ExternalClient {
+	return &external{
+		kind:   kind,
+		id:     id,
+		client: e,
+	}
+}
+
+type external struct {
+	kind   string
+	id     func(resource.Managed) int64
+	client managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (obs managed.ExternalObservation, err error) {
+	ctx, span := e.start(ctx, "Observe", mg)
+	defer func() { e.end(span, mg, err) }()
+
+	obs, err = e.client.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("crossplane.resource_exists", obs.ResourceExists),
+		attribute.Bool("crossplane.resource_up_to_date", obs.ResourceUpToDate),
+	)
+
+	return obs, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (cre managed.ExternalCreation, err error) {
+	ctx, span := e.start(ctx, "Create", mg)
+	defer func() { e.end(span, mg, err) }()
+
+	return e.client.Create(ctx, mg)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (upd managed.ExternalUpdate, err error) {
+	ctx, span := e.start(ctx, "Update", mg)
+	defer func() { e.end(span, mg, err) }()
+
+	return e.client.Update(ctx, mg)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) (err error) {
+	ctx, span := e.start(ctx, "Delete", mg)
+	defer func() { e.end(span, mg, err) }()
+
+	return e.client.Delete(ctx, mg)
+}
+
+func (e *external) start(ctx context.Context, method string, mg resource.Managed) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, e.kind+"."+method, trace.WithAttributes(
+		KindKey.String(e.kind),
+		NameKey.String(mg.GetName()),
+	))
+}
+
+// end tags the span with the Hetzner ID, which is only known after Create,
+// and records any error before ending it
+func (e *external) end(span trace.Span, mg resource.Managed, err error) {
+	if id := e.id(mg); id != 0 {
+		span.SetAttributes(hcloud.IDKey.Int64(id))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+)
+
+func TestExternalClient(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		name   string
+		attrs  map[attribute.Key]attribute.Value
+		status codes.Code
+	}
+
+	cases := map[string]struct {
+		reason string
+		client managed.ExternalClient
+		call   func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error
+		want   want
+	}{
+		"Observe": {
+			reason: "Observe should be traced with the resource's kind, name, Hetzner ID and observation",
+			client: &managed.ExternalClientFns{
+				ObserveFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{ResourceExists: true}, nil
+				},
+			},
+			call: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Observe(ctx, mg)
+				return err
+			},
+			want: want{
+				name: "Server.Observe",
+				attrs: map[attribute.Key]attribute.Value{
+					KindKey:                          attribute.StringValue("Server"),
+					NameKey:                          attribute.StringValue("example"),
+					hcloud.IDKey:                     attribute.Int64Value(42),
+					"crossplane.resource_exists":     attribute.BoolValue(true),
+					"crossplane.resource_up_to_date": attribute.BoolValue(false),
+				},
+				status: codes.Unset,
+			},
+		},
+		"DeleteFailed": {
+			reason: "Errors should be recorded on the span",
+			client: &managed.ExternalClientFns{
+				DeleteFn: func(ctx context.Context, mg resource.Managed) error {
+					return errBoom
+				},
+			},
+			call: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				return e.Delete(ctx, mg)
+			},
+			want: want{
+				name: "Server.Delete",
+				attrs: map[attribute.Key]attribute.Value{
+					KindKey:      attribute.StringValue("Server"),
+					NameKey:      attribute.StringValue("example"),
+					hcloud.IDKey: attribute.Int64Value(42),
+				},
+				status: codes.Error,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := otel.GetTracerProvider()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			defer otel.SetTracerProvider(tp)
+
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "example"}}
+			e := NewExternalClient("Server", func(resource.Managed) int64 { return 42 }, tc.client)
+
+			_ = tc.call(context.Background(), e, mg)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("\n%s\nwant 1 span, got %d", tc.reason, len(spans))
+			}
+
+			got := want{
+				name:   spans[0].Name(),
+				attrs:  map[attribute.Key]attribute.Value{},
+				status: spans[0].Status().Code,
+			}
+			for _, kv := range spans[0].Attributes() {
+				got.attrs[kv.Key] = kv.Value
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmp.AllowUnexported(attribute.Value{})); diff != "" {
+				t.Errorf("\n%s\nspan: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
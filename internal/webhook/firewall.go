@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+const errNotFirewall = "object is not a Firewall"
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cloud-hetzner-crossplane-io-v1alpha1-firewall,mutating=false,failurePolicy=fail,groups=cloud.hetzner.crossplane.io,resources=firewalls,versions=v1alpha1,name=firewalls.cloud.hetzner.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func setupFirewall(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Firewall{}).
+		WithValidator(&firewallValidator{}).
+		Complete()
+}
+
+type firewallValidator struct{}
+
+func (v *firewallValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.Firewall)
+	if !ok {
+		return nil, errors.New(errNotFirewall)
+	}
+
+	return nil, v.validate(cr)
+}
+
+func (v *firewallValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1alpha1.Firewall)
+	if !ok {
+		return nil, errors.New(errNotFirewall)
+	}
+	cr, ok := newObj.(*v1alpha1.Firewall)
+	if !ok {
+		return nil, errors.New(errNotFirewall)
+	}
+
+	if unvalidated(cr, old.Spec, cr.Spec) {
+		return nil, nil
+	}
+
+	// Every firewall field can be updated in place
+	return nil, v.validate(cr)
+}
+
+func (v *firewallValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *firewallValidator) validate(cr *v1alpha1.Firewall) error {
+	var errs field.ErrorList
+
+	for i, rule := range cr.Spec.ForProvider.Rules {
+		errs = append(errs, validateFirewallRule(forProvider.Child("rules").Index(i), rule)...)
+	}
+
+	for i, applyTo := range cr.Spec.ForProvider.ApplyTo {
+		errs = append(errs, validateFirewallApplyTo(forProvider.Child("applyTo").Index(i), applyTo)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return kerrors.NewInvalid(v1alpha1.FirewallGroupVersionKind.GroupKind(), cr.GetName(), errs)
+}
+
+func validateFirewallRule(path *field.Path, rule v1alpha1.FirewallRules) field.ErrorList {
+	var errs field.ErrorList
+
+	switch rule.Direction {
+	case hcloudsdk.FirewallRuleDirectionIn, hcloudsdk.FirewallRuleDirectionOut:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("direction"), rule.Direction, []string{
+			string(hcloudsdk.FirewallRuleDirectionIn),
+			string(hcloudsdk.FirewallRuleDirectionOut),
+		}))
+	}
+
+	switch rule.Protocol {
+	case hcloudsdk.FirewallRuleProtocolTCP, hcloudsdk.FirewallRuleProtocolUDP,
+		hcloudsdk.FirewallRuleProtocolICMP, hcloudsdk.FirewallRuleProtocolESP, hcloudsdk.FirewallRuleProtocolGRE:
+		errs = append(errs, validateFirewallPort(path.Child("port"), rule)...)
+	default:
+		errs = append(errs, field.NotSupported(path.Child("protocol"), rule.Protocol, []string{
+			string(hcloudsdk.FirewallRuleProtocolTCP),
+			string(hcloudsdk.FirewallRuleProtocolUDP),
+			string(hcloudsdk.FirewallRuleProtocolICMP),
+			string(hcloudsdk.FirewallRuleProtocolESP),
+			string(hcloudsdk.FirewallRuleProtocolGRE),
+		}))
+	}
+
+	errs = append(errs, validateFirewallIPs(path.Child("sourceIPs"), rule.SourceIPs)...)
+	errs = append(errs, validateFirewallIPs(path.Child("destinationIPs"), rule.DestinationIPs)...)
+	errs = append(errs, validateFirewallIPs(path.Child("targetIPs"), rule.TargetIPs)...)
+
+	source, destination := rule.RuleIPs()
+	switch rule.Direction {
+	case hcloudsdk.FirewallRuleDirectionIn:
+		if len(rule.DestinationIPs) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("destinationIPs"), "in rules only have sourceIPs"))
+		} else if len(source) == 0 {
+			errs = append(errs, field.Required(path.Child("sourceIPs"), "in rules need at least one source IP"))
+		}
+	case hcloudsdk.FirewallRuleDirectionOut:
+		if len(rule.SourceIPs) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("sourceIPs"), "out rules only have destinationIPs"))
+		} else if len(destination) == 0 {
+			errs = append(errs, field.Required(path.Child("destinationIPs"), "out rules need at least one destination IP"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Anything the controller would fail to convert is invalid too
+	if _, err := rule.ToFirewallRule(); err != nil {
+		errs = append(errs, field.Invalid(path, rule, err.Error()))
+	}
+
+	return errs
+}
+
+func validateFirewallIPs(path *field.Path, ips []string) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, ip := range ips {
+		switch ip {
+		case v1alpha1.FirewallAnyIP, v1alpha1.FirewallAnyIPv4, v1alpha1.FirewallAnyIPv6:
+			continue
+		}
+		if _, err := validateCIDR(path.Index(i), ip); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// validateFirewallPort reports the rule's port problems, as found by the
+// API types, as field errors
+func validateFirewallPort(path *field.Path, rule v1alpha1.FirewallRules) field.ErrorList {
+	if err := rule.ValidatePort(); err != nil {
+		return field.ErrorList{field.Invalid(path, rule.Port.String(), err.Error())}
+	}
+	return nil
+}
+
+func validateFirewallApplyTo(path *field.Path, applyTo v1alpha1.FirewallApplyTo) field.ErrorList {
+	var errs field.ErrorList
+
+	switch applyTo.Type {
+	case hcloudsdk.FirewallResourceTypeServer:
+		if applyTo.ServerID == nil {
+			errs = append(errs, field.Required(path.Child("serverID"), "server targets need a serverID"))
+		}
+	case hcloudsdk.FirewallResourceTypeLabelSelector:
+		if applyTo.Labels == nil && applyTo.LabelSelector == nil {
+			errs = append(errs, field.Required(path, "label_selector targets need labels or a labelSelector"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), applyTo.Type, []string{
+			string(hcloudsdk.FirewallResourceTypeServer),
+			string(hcloudsdk.FirewallResourceTypeLabelSelector),
+		}))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if _, err := applyTo.ToFirewallResource(); err != nil {
+		errs = append(errs, field.Invalid(path, applyTo, err.Error()))
+	}
+
+	return errs
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+func firewall(rules ...v1alpha1.FirewallRules) *v1alpha1.Firewall {
+	return &v1alpha1.Firewall{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: v1alpha1.FirewallSpec{
+			ForProvider: v1alpha1.FirewallParameters{Rules: rules},
+		},
+	}
+}
+
+func withFirewallLabels(cr *v1alpha1.Firewall, labels map[string]string) *v1alpha1.Firewall {
+	cr.SetLabels(labels)
+	return cr
+}
+
+func TestFirewallValidateUpdate(t *testing.T) {
+	// Firewalls created before ports were validated left them out of tcp
+	// and udp rules to match every port
+	portless := v1alpha1.FirewallRules{
+		Direction: hcloudsdk.FirewallRuleDirectionIn,
+		Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+		TargetIPs: []string{"0.0.0.0/0"},
+	}
+
+	icmpWithPort := v1alpha1.FirewallRules{
+		Direction: hcloudsdk.FirewallRuleDirectionIn,
+		Protocol:  hcloudsdk.FirewallRuleProtocolICMP,
+		TargetIPs: []string{"0.0.0.0/0"},
+		Port:      &v1alpha1.FirewallPort{All: true},
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.Firewall
+		new    *v1alpha1.Firewall
+		want   error
+	}{
+		"LegacyPortlessRule": {
+			reason: "A firewall with a tcp rule that has no port should still be updatable",
+			old:    firewall(portless),
+			new:    withFirewallLabels(firewall(portless), map[string]string{"env": "test"}),
+		},
+		"RemoveFinalizer": {
+			reason: "An invalid firewall being deleted should be able to have its finalizer removed",
+			old: func() *v1alpha1.Firewall {
+				cr := firewall(icmpWithPort)
+				cr.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+				cr.SetDeletionTimestamp(&metav1.Time{})
+				return cr
+			}(),
+			new: func() *v1alpha1.Firewall {
+				cr := firewall(icmpWithPort)
+				cr.SetDeletionTimestamp(&metav1.Time{})
+				return cr
+			}(),
+		},
+		"InvalidRule": {
+			reason: "Changing a rule to an invalid one should be rejected",
+			old:    firewall(portless),
+			new:    firewall(icmpWithPort),
+			want: kerrors.NewInvalid(v1alpha1.FirewallGroupVersionKind.GroupKind(), "example", field.ErrorList{
+				field.Invalid(forProvider.Child("rules").Index(0).Child("port"), "any", "icmp rules cannot have a port"),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &firewallValidator{}
+			_, err := v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallRule(t *testing.T) {
+	path := forProvider.Child("rules").Index(0)
+
+	cases := map[string]struct {
+		reason string
+		rule   v1alpha1.FirewallRules
+		want   field.ErrorList
+	}{
+		"ValidTCP": {
+			reason: "A TCP rule with a port range and valid CIDRs should be accepted",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				TargetIPs: []string{"0.0.0.0/0", "::/0"},
+				Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(80), End: hcloudsdk.Ptr(443)},
+			},
+		},
+		"ValidICMP": {
+			reason: "An ICMP rule does not need a port",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionOut,
+				Protocol:  hcloudsdk.FirewallRuleProtocolICMP,
+				TargetIPs: []string{"10.0.0.0/8"},
+			},
+		},
+		"TCPWithoutPort": {
+			reason: "A TCP rule without a port matches every port",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				TargetIPs: []string{"0.0.0.0/0"},
+			},
+		},
+		"ICMPWithPort": {
+			reason: "An ICMP rule cannot have a port",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolICMP,
+				TargetIPs: []string{"0.0.0.0/0"},
+				Port:      &v1alpha1.FirewallPort{All: true},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("port"), "any", "icmp rules cannot have a port"),
+			},
+		},
+		"InvalidPortRange": {
+			reason: "The end of a port range cannot be before the start",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolUDP,
+				TargetIPs: []string{"0.0.0.0/0"},
+				Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(443), End: hcloudsdk.Ptr(80)},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("port"), "443-80", "port range 443-80 ends before it starts"),
+			},
+		},
+		"MalformedCIDR": {
+			reason: "Each target IP must be a CIDR",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				TargetIPs: []string{"0.0.0.0/0", "10.0.0.1"},
+				Port:      &v1alpha1.FirewallPort{All: true},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("targetIPs").Index(1), "10.0.0.1", "must be a CIDR, such as 10.0.0.0/16"),
+			},
+		},
+		"AnyShortcuts": {
+			reason: "The any shortcuts should be accepted in place of CIDRs",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolESP,
+				SourceIPs: []string{v1alpha1.FirewallAnyIPv4, v1alpha1.FirewallAnyIPv6},
+			},
+		},
+		"MalformedSourceIP": {
+			reason: "Each source IP must be a CIDR or an any shortcut",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolICMP,
+				SourceIPs: []string{"anywhere"},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("sourceIPs").Index(0), "anywhere", "must be a CIDR, such as 10.0.0.0/16"),
+			},
+		},
+		"SourceIPsOnOutRule": {
+			reason: "An out rule cannot have source IPs",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionOut,
+				Protocol:  hcloudsdk.FirewallRuleProtocolGRE,
+				SourceIPs: []string{v1alpha1.FirewallAnyIP},
+			},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("sourceIPs"), "out rules only have destinationIPs"),
+			},
+		},
+		"NoIPs": {
+			reason: "An in rule needs at least one source IP",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolICMP,
+			},
+			want: field.ErrorList{
+				field.Required(path.Child("sourceIPs"), "in rules need at least one source IP"),
+			},
+		},
+		"PortAboveMax": {
+			reason: "Ports cannot be above 65535",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				SourceIPs: []string{v1alpha1.FirewallAnyIP},
+				Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(65536)},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("port"), "65536", "port 65536 must be between 1 and 65535"),
+			},
+		},
+		"PortBelowMin": {
+			reason: "Ports cannot be below 1",
+			rule: v1alpha1.FirewallRules{
+				Direction: hcloudsdk.FirewallRuleDirectionIn,
+				Protocol:  hcloudsdk.FirewallRuleProtocolTCP,
+				SourceIPs: []string{v1alpha1.FirewallAnyIP},
+				Port:      &v1alpha1.FirewallPort{Start: hcloudsdk.Ptr(0)},
+			},
+			want: field.ErrorList{
+				field.Invalid(path.Child("port"), "0", "port 0 must be between 1 and 65535"),
+			},
+		},
+		"UnknownDirection": {
+			reason: "The direction must be in or out",
+			rule: v1alpha1.FirewallRules{
+				Direction: "sideways",
+				Protocol:  hcloudsdk.FirewallRuleProtocolGRE,
+				TargetIPs: []string{"0.0.0.0/0"},
+			},
+			want: field.ErrorList{
+				field.NotSupported(path.Child("direction"), hcloudsdk.FirewallRuleDirection("sideways"), []string{"in", "out"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validateFirewallRule(path, tc.rule)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateFirewallRule(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+const errNotNetwork = "object is not a Network"
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cloud-hetzner-crossplane-io-v1alpha1-network,mutating=false,failurePolicy=fail,groups=cloud.hetzner.crossplane.io,resources=networks,versions=v1alpha1,name=networks.cloud.hetzner.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func setupNetwork(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Network{}).
+		WithValidator(&networkValidator{}).
+		Complete()
+}
+
+type networkValidator struct{}
+
+func (v *networkValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.Network)
+	if !ok {
+		return nil, errors.New(errNotNetwork)
+	}
+
+	return nil, v.validate(cr)
+}
+
+func (v *networkValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1alpha1.Network)
+	if !ok {
+		return nil, errors.New(errNotNetwork)
+	}
+	cr, ok := newObj.(*v1alpha1.Network)
+	if !ok {
+		return nil, errors.New(errNotNetwork)
+	}
+
+	if unvalidated(cr, old.Spec, cr.Spec) {
+		return nil, nil
+	}
+
+	was, is := old.Spec.ForProvider, cr.Spec.ForProvider
+
+	return immutable(old.Status.AtProvider.ID != 0,
+		change{forProvider.Child("subnets"), was.Subnets, is.Subnets},
+		change{forProvider.Child("routes"), was.Routes, is.Routes},
+	), v.validate(cr)
+}
+
+func (v *networkValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *networkValidator) validate(cr *v1alpha1.Network) error {
+	p := cr.Spec.ForProvider
+
+	ipRange, err := validateCIDR(forProvider.Child("ipRange"), p.IPRange)
+	if err != nil {
+		return kerrors.NewInvalid(v1alpha1.NetworkGroupVersionKind.GroupKind(), cr.GetName(), field.ErrorList{err})
+	}
+
+	var errs field.ErrorList
+
+	for i, subnet := range p.Subnets {
+		path := forProvider.Child("subnets").Index(i).Child("ipRange")
+
+		subnetRange, err := validateCIDR(path, subnet.IPRange)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !containsNet(ipRange, subnetRange) {
+			errs = append(errs, field.Invalid(path, subnet.IPRange, "must be within the network's ipRange "+p.IPRange))
+		}
+	}
+
+	for i, route := range p.Routes {
+		path := forProvider.Child("routes").Index(i)
+
+		if _, err := validateCIDR(path.Child("destination"), route.Destination); err != nil {
+			errs = append(errs, err)
+		}
+
+		gateway := net.ParseIP(route.Gateway)
+		switch {
+		case gateway == nil:
+			errs = append(errs, field.Invalid(path.Child("gateway"), route.Gateway, "must be an IP address"))
+		case !ipRange.Contains(gateway):
+			errs = append(errs, field.Invalid(path.Child("gateway"), route.Gateway, "must be within the network's ipRange "+p.IPRange))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return kerrors.NewInvalid(v1alpha1.NetworkGroupVersionKind.GroupKind(), cr.GetName(), errs)
+}
+
+// containsNet reports whether inner lies entirely within outer
+func containsNet(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+
+	return outerBits == innerBits && innerOnes >= outerOnes && outer.Contains(inner.IP)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+func network(p v1alpha1.NetworkParameters) *v1alpha1.Network {
+	return &v1alpha1.Network{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       v1alpha1.NetworkSpec{ForProvider: p},
+	}
+}
+
+func TestNetworkValidate(t *testing.T) {
+	invalid := func(errs ...*field.Error) error {
+		return kerrors.NewInvalid(v1alpha1.NetworkGroupVersionKind.GroupKind(), "example", errs)
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.NetworkParameters
+		want   error
+	}{
+		"Valid": {
+			reason: "Subnets and route gateways within the IP range should be accepted",
+			params: v1alpha1.NetworkParameters{
+				IPRange: "10.0.0.0/16",
+				Subnets: []v1alpha1.NetworkSubnet{{IPRange: "10.0.1.0/24"}},
+				Routes:  []v1alpha1.NetworkRoute{{Destination: "10.100.1.0/24", Gateway: "10.0.1.1"}},
+			},
+		},
+		"MalformedIPRange": {
+			reason: "The IP range must be a CIDR",
+			params: v1alpha1.NetworkParameters{IPRange: "10.0.0.0"},
+			want:   invalid(field.Invalid(forProvider.Child("ipRange"), "10.0.0.0", "must be a CIDR, such as 10.0.0.0/16")),
+		},
+		"SubnetOutsideIPRange": {
+			reason: "Subnets must lie entirely within the IP range",
+			params: v1alpha1.NetworkParameters{
+				IPRange: "10.0.0.0/16",
+				Subnets: []v1alpha1.NetworkSubnet{
+					{IPRange: "10.1.0.0/24"},
+					{IPRange: "10.0.0.0/8"},
+				},
+			},
+			want: invalid(
+				field.Invalid(forProvider.Child("subnets").Index(0).Child("ipRange"), "10.1.0.0/24", "must be within the network's ipRange 10.0.0.0/16"),
+				field.Invalid(forProvider.Child("subnets").Index(1).Child("ipRange"), "10.0.0.0/8", "must be within the network's ipRange 10.0.0.0/16"),
+			),
+		},
+		"InvalidRoute": {
+			reason: "Route destinations must be CIDRs and gateways must be within the IP range",
+			params: v1alpha1.NetworkParameters{
+				IPRange: "10.0.0.0/16",
+				Routes:  []v1alpha1.NetworkRoute{{Destination: "nowhere", Gateway: "192.168.0.1"}},
+			},
+			want: invalid(
+				field.Invalid(forProvider.Child("routes").Index(0).Child("destination"), "nowhere", "must be a CIDR, such as 10.0.0.0/16"),
+				field.Invalid(forProvider.Child("routes").Index(0).Child("gateway"), "192.168.0.1", "must be within the network's ipRange 10.0.0.0/16"),
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &networkValidator{}
+			err := v.validate(network(tc.params))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
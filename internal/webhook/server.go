@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+const errNotServer = "object is not a Server"
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cloud-hetzner-crossplane-io-v1alpha1-server,mutating=false,failurePolicy=fail,groups=cloud.hetzner.crossplane.io,resources=servers,versions=v1alpha1,name=servers.cloud.hetzner.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func setupServer(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Server{}).
+		WithValidator(&serverValidator{}).
+		Complete()
+}
+
+type serverValidator struct{}
+
+func (v *serverValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.Server)
+	if !ok {
+		return nil, errors.New(errNotServer)
+	}
+
+	return nil, v.validate(cr, nil)
+}
+
+func (v *serverValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1alpha1.Server)
+	if !ok {
+		return nil, errors.New(errNotServer)
+	}
+	cr, ok := newObj.(*v1alpha1.Server)
+	if !ok {
+		return nil, errors.New(errNotServer)
+	}
+
+	// Unlike the other resources, an update to the metadata alone can still
+	// request an operation, so only deletion skips validation outright
+	if cr.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	was, is := old.Spec.ForProvider, cr.Spec.ForProvider
+
+	return append(replacing(cr), immutable(old.Status.AtProvider.ID != 0,
+		change{forProvider.Child("serverType"), was.ServerType, is.ServerType},
+		change{forProvider.Child("autoMount"), was.AutoMount, is.AutoMount},
+		change{forProvider.Child("enableIPv4"), was.EnableIPv4, is.EnableIPv4},
+		change{forProvider.Child("enableIPv6"), was.EnableIPv6, is.EnableIPv6},
+		change{forProvider.Child("sshKeys"), was.SSHKeys, is.SSHKeys},
+		change{forProvider.Child("generateSSHKey"), was.GenerateSSHKey, is.GenerateSSHKey},
+		change{forProvider.Child("generateHostKey"), was.GenerateHostKey, is.GenerateHostKey},
+		change{forProvider.Child("startAfterCreate"), was.StartAfterCreate, is.StartAfterCreate},
+		change{forProvider.Child("userData"), was.UserData, is.UserData},
+		change{forProvider.Child("userDataFrom"), was.UserDataFrom, is.UserDataFrom},
+	)...), v.validate(cr, old)
+}
+
+func (v *serverValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks a new server, or what an update changed: the parameters if
+// the spec changed and the operation if a different one was requested.
+func (v *serverValidator) validate(cr, old *v1alpha1.Server) error {
+	var errs field.ErrorList
+
+	if old == nil || !unvalidated(cr, old.Spec, cr.Spec) {
+		errs = append(errs, validateServerParameters(cr.Spec.ForProvider)...)
+	}
+
+	op, requested := cr.GetAnnotations()[apisv1alpha1.AnnotationKeyOperation]
+	if old != nil && old.GetAnnotations()[apisv1alpha1.AnnotationKeyOperation] == op {
+		requested = false
+	}
+	if requested && !slices.Contains(apisv1alpha1.ServerOperations, apisv1alpha1.ServerOperation(op)) {
+		errs = append(errs, field.NotSupported(field.NewPath("metadata", "annotations").Key(apisv1alpha1.AnnotationKeyOperation), op, apisv1alpha1.ServerOperations))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return kerrors.NewInvalid(v1alpha1.ServerGroupVersionKind.GroupKind(), cr.GetName(), errs)
+}
+
+func validateServerParameters(p v1alpha1.ServerParameters) field.ErrorList {
+	var errs field.ErrorList
+
+	if p.Image == "" {
+		errs = append(errs, field.Required(forProvider.Child("image"), ""))
+	}
+	if p.ServerType == "" {
+		errs = append(errs, field.Required(forProvider.Child("serverType"), ""))
+	}
+
+	switch {
+	case p.Datacenter == nil && p.Location == nil:
+		errs = append(errs, field.Required(forProvider, "one of datacenter or location is required"))
+	case p.Datacenter != nil && p.Location != nil:
+		errs = append(errs, field.Forbidden(forProvider.Child("location"), "only one of datacenter or location may be set"))
+	}
+
+	if p.PowerSchedule != nil {
+		if err := p.PowerSchedule.Validate(); err != nil {
+			errs = append(errs, field.Invalid(forProvider.Child("powerSchedule"), p.PowerSchedule, err.Error()))
+		}
+	}
+
+	return errs
+}
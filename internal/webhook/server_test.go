@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+func server(id int64, p v1alpha1.ServerParameters) *v1alpha1.Server {
+	return &v1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       v1alpha1.ServerSpec{ForProvider: p},
+		Status: v1alpha1.ServerStatus{
+			AtProvider: v1alpha1.ServerObservation{ID: id},
+		},
+	}
+}
+
+func withReplacement(cr *v1alpha1.Server) *v1alpha1.Server {
+	cr.Spec.ReplacementPolicy = apisv1alpha1.ReplacementPolicyReplace
+	cr.Status.AtProvider.ServerParameters = &v1alpha1.ServerParameters{
+		Image:      "ubuntu-22.04",
+		ServerType: "cx11",
+		Location:   hcloudsdk.Ptr("fsn1"),
+	}
+	return cr
+}
+
+func withPowerSchedule(p v1alpha1.ServerParameters, schedule *apisv1alpha1.PowerSchedule) v1alpha1.ServerParameters {
+	p.PowerSchedule = schedule
+	return p
+}
+
+// finalized marks a server as deleted, with the finalizer that's holding it
+func finalized(cr *v1alpha1.Server) *v1alpha1.Server {
+	cr.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	cr.SetDeletionTimestamp(&metav1.Time{})
+	return cr
+}
+
+func TestServerValidateUpdate(t *testing.T) {
+	valid := v1alpha1.ServerParameters{
+		Image:      "ubuntu-22.04",
+		ServerType: "cx11",
+		Location:   hcloudsdk.Ptr("fsn1"),
+	}
+
+	// Servers created before datacenter and location were mutually exclusive
+	// may have both
+	legacy := valid
+	legacy.Datacenter = hcloudsdk.Ptr("fsn1-dc14")
+
+	moved := valid
+	moved.Location = hcloudsdk.Ptr("nbg1")
+	moved.ServerType = "cx21"
+
+	type want struct {
+		warnings admission.Warnings
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.Server
+		new    *v1alpha1.Server
+		want   want
+	}{
+		"NotCreated": {
+			reason: "Fields can be changed freely before the server is created",
+			old:    server(0, valid),
+			new:    server(0, moved),
+		},
+		"ImmutableChanged": {
+			reason: "Changing immutable fields of a created server should be warned about",
+			old:    server(42, valid),
+			new:    server(42, moved),
+			want: want{
+				warnings: admission.Warnings{
+					"spec.forProvider.serverType cannot be changed once the resource is created and will be ignored",
+				},
+			},
+		},
+		"Replace": {
+			reason: "Changing create-only fields with the Replace policy should warn that the server will be recreated",
+			old:    withReplacement(server(42, valid)),
+			new:    withReplacement(server(42, v1alpha1.ServerParameters{Image: "debian-12", ServerType: "cx11", Location: hcloudsdk.Ptr("fsn1")})),
+			want: want{
+				warnings: admission.Warnings{
+					"create-only fields have changed, so the Hetzner resource will be deleted and recreated",
+				},
+			},
+		},
+		"NoLocation": {
+			reason: "A server needs either a datacenter or a location",
+			old:    server(0, valid),
+			new:    server(0, v1alpha1.ServerParameters{Image: "ubuntu-22.04", ServerType: "cx11"}),
+			want: want{
+				err: kerrors.NewInvalid(v1alpha1.ServerGroupVersionKind.GroupKind(), "example", field.ErrorList{
+					field.Required(forProvider, "one of datacenter or location is required"),
+				}),
+			},
+		},
+		"DatacenterAndLocation": {
+			reason: "A server cannot have both a datacenter and a location",
+			old:    server(0, valid),
+			new: server(0, v1alpha1.ServerParameters{
+				Image:      "ubuntu-22.04",
+				ServerType: "cx11",
+				Datacenter: hcloudsdk.Ptr("fsn1-dc14"),
+				Location:   hcloudsdk.Ptr("fsn1"),
+			}),
+			want: want{
+				err: kerrors.NewInvalid(v1alpha1.ServerGroupVersionKind.GroupKind(), "example", field.ErrorList{
+					field.Forbidden(forProvider.Child("location"), "only one of datacenter or location may be set"),
+				}),
+			},
+		},
+		"InvalidPowerSchedule": {
+			reason: "A power schedule must be in a known time zone",
+			old:    server(0, valid),
+			new:    server(0, withPowerSchedule(valid, &apisv1alpha1.PowerSchedule{On: "0 8 * * 1-5", Off: "0 20 * * 1-5", TimeZone: "Europe/Nowhere"})),
+			want: want{
+				err: kerrors.NewInvalid(v1alpha1.ServerGroupVersionKind.GroupKind(), "example", field.ErrorList{
+					field.Invalid(forProvider.Child("powerSchedule"), &apisv1alpha1.PowerSchedule{On: "0 8 * * 1-5", Off: "0 20 * * 1-5", TimeZone: "Europe/Nowhere"}, `invalid time zone "Europe/Nowhere"`),
+				}),
+			},
+		},
+		"UnknownOperation": {
+			reason: "Only known operations can be requested",
+			old:    server(42, valid),
+			new: func() *v1alpha1.Server {
+				cr := server(42, valid)
+				cr.SetAnnotations(map[string]string{apisv1alpha1.AnnotationKeyOperation: "Rebuild"})
+				return cr
+			}(),
+			want: want{
+				err: kerrors.NewInvalid(v1alpha1.ServerGroupVersionKind.GroupKind(), "example", field.ErrorList{
+					field.NotSupported(field.NewPath("metadata", "annotations").Key(apisv1alpha1.AnnotationKeyOperation), "Rebuild", apisv1alpha1.ServerOperations),
+				}),
+			},
+		},
+		"RemoveFinalizer": {
+			reason: "An invalid server being deleted should be able to have its finalizer removed",
+			old:    finalized(server(42, legacy)),
+			new: func() *v1alpha1.Server {
+				cr := finalized(server(42, legacy))
+				cr.SetFinalizers(nil)
+				return cr
+			}(),
+		},
+		"MetadataOnly": {
+			reason: "An invalid server should be able to have its metadata changed",
+			old:    server(42, legacy),
+			new: func() *v1alpha1.Server {
+				cr := server(42, legacy)
+				cr.SetLabels(map[string]string{"env": "test"})
+				return cr
+			}(),
+		},
+		"OperationOnInvalid": {
+			reason: "A known operation should be able to be requested on an invalid server",
+			old:    server(42, legacy),
+			new: func() *v1alpha1.Server {
+				cr := server(42, legacy)
+				cr.SetAnnotations(map[string]string{apisv1alpha1.AnnotationKeyOperation: string(apisv1alpha1.ServerOperationReboot)})
+				return cr
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &serverValidator{}
+			warnings, err := v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, warnings); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want warnings, +got warnings:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
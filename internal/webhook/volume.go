@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+const (
+	errNotVolume = "object is not a Volume"
+
+	// Volume sizes in GB allowed by Hetzner
+	minVolumeSize = 10
+	maxVolumeSize = 10240
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cloud-hetzner-crossplane-io-v1alpha1-volume,mutating=false,failurePolicy=fail,groups=cloud.hetzner.crossplane.io,resources=volumes,versions=v1alpha1,name=volumes.cloud.hetzner.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func setupVolume(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Volume{}).
+		WithValidator(&volumeValidator{}).
+		Complete()
+}
+
+type volumeValidator struct{}
+
+func (v *volumeValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*v1alpha1.Volume)
+	if !ok {
+		return nil, errors.New(errNotVolume)
+	}
+
+	return nil, v.validate(cr, nil)
+}
+
+func (v *volumeValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1alpha1.Volume)
+	if !ok {
+		return nil, errors.New(errNotVolume)
+	}
+	cr, ok := newObj.(*v1alpha1.Volume)
+	if !ok {
+		return nil, errors.New(errNotVolume)
+	}
+
+	if unvalidated(cr, old.Spec, cr.Spec) {
+		return nil, nil
+	}
+
+	return replacing(cr), v.validate(cr, old)
+}
+
+func (v *volumeValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *volumeValidator) validate(cr, old *v1alpha1.Volume) error {
+	p := cr.Spec.ForProvider
+	var errs field.ErrorList
+
+	if p.Size < minVolumeSize || p.Size > maxVolumeSize {
+		errs = append(errs, field.Invalid(forProvider.Child("size"), p.Size, "must be between 10 and 10240 GB"))
+	}
+
+	// Volumes can grow but never shrink
+	if old != nil && old.Status.AtProvider.ID != 0 && p.Size < old.Spec.ForProvider.Size {
+		errs = append(errs, field.Invalid(forProvider.Child("size"), p.Size, "cannot be reduced once the volume is created"))
+	}
+
+	if p.Location == nil && p.ServerID == nil {
+		errs = append(errs, field.Required(forProvider, "one of location or serverID is required"))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return kerrors.NewInvalid(v1alpha1.VolumeGroupVersionKind.GroupKind(), cr.GetName(), errs)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mrsimonemms/provider-hetzner/apis/cloud/v1alpha1"
+)
+
+func volume(id int64, p v1alpha1.VolumeParameters) *v1alpha1.Volume {
+	return &v1alpha1.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       v1alpha1.VolumeSpec{ForProvider: p},
+		Status: v1alpha1.VolumeStatus{
+			AtProvider: v1alpha1.VolumeObservation{ID: id},
+		},
+	}
+}
+
+func TestVolumeValidate(t *testing.T) {
+	invalid := func(errs ...*field.Error) error {
+		return kerrors.NewInvalid(v1alpha1.VolumeGroupVersionKind.GroupKind(), "example", errs)
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.Volume
+		new    *v1alpha1.Volume
+		want   error
+	}{
+		"Valid": {
+			reason: "A volume with a location and a valid size should be accepted",
+			new:    volume(0, v1alpha1.VolumeParameters{Size: 10, Location: hcloudsdk.Ptr("fsn1")}),
+		},
+		"Grow": {
+			reason: "A created volume can be resized upwards",
+			old:    volume(42, v1alpha1.VolumeParameters{Size: 10, Location: hcloudsdk.Ptr("fsn1")}),
+			new:    volume(42, v1alpha1.VolumeParameters{Size: 20, Location: hcloudsdk.Ptr("fsn1")}),
+		},
+		"Shrink": {
+			reason: "A created volume cannot be resized downwards",
+			old:    volume(42, v1alpha1.VolumeParameters{Size: 20, ServerID: hcloudsdk.Ptr[int64](1)}),
+			new:    volume(42, v1alpha1.VolumeParameters{Size: 10, ServerID: hcloudsdk.Ptr[int64](1)}),
+			want:   invalid(field.Invalid(forProvider.Child("size"), 10, "cannot be reduced once the volume is created")),
+		},
+		"Invalid": {
+			reason: "A volume needs a size in range and either a location or a server",
+			new:    volume(0, v1alpha1.VolumeParameters{Size: 5}),
+			want: invalid(
+				field.Invalid(forProvider.Child("size"), 5, "must be between 10 and 10240 GB"),
+				field.Required(forProvider, "one of location or serverID is required"),
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &volumeValidator{}
+			err := v.validate(tc.new, tc.old)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVolumeValidateUpdate(t *testing.T) {
+	// Volumes smaller than Hetzner allows could be created before their size
+	// was validated
+	legacy := v1alpha1.VolumeParameters{Size: 5, Location: hcloudsdk.Ptr("fsn1")}
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.Volume
+		new    *v1alpha1.Volume
+		want   error
+	}{
+		"RemoveFinalizer": {
+			reason: "An invalid volume being deleted should be able to have its finalizer removed",
+			old: func() *v1alpha1.Volume {
+				cr := volume(42, legacy)
+				cr.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+				cr.SetDeletionTimestamp(&metav1.Time{})
+				return cr
+			}(),
+			new: func() *v1alpha1.Volume {
+				cr := volume(42, legacy)
+				cr.SetDeletionTimestamp(&metav1.Time{})
+				return cr
+			}(),
+		},
+		"MetadataOnly": {
+			reason: "An invalid volume should be able to have its metadata changed",
+			old:    volume(42, legacy),
+			new: func() *v1alpha1.Volume {
+				cr := volume(42, legacy)
+				cr.SetLabels(map[string]string{"env": "test"})
+				return cr
+			}(),
+		},
+		"SpecChanged": {
+			reason: "Changing the spec of an invalid volume should be validated",
+			old:    volume(42, legacy),
+			new:    volume(42, v1alpha1.VolumeParameters{Size: 6, Location: hcloudsdk.Ptr("fsn1")}),
+			want: kerrors.NewInvalid(v1alpha1.VolumeGroupVersionKind.GroupKind(), "example", field.ErrorList{
+				field.Invalid(forProvider.Child("size"), 6, "must be between 10 and 10240 GB"),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &volumeValidator{}
+			_, err := v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
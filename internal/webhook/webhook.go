@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook rejects invalid managed resources when they are applied,
+// rather than when the controllers first try to create them in Hetzner.
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// forProvider is the path of the parameters of every managed resource
+var forProvider = field.NewPath("spec", "forProvider")
+
+// Setup adds a validating webhook for each managed resource kind to the
+// manager's webhook server, along with the conversion webhook that serves
+// every API version of the kinds.
+func Setup(mgr ctrl.Manager) error {
+	for _, setup := range []func(ctrl.Manager) error{
+		setupFirewall,
+		setupNetwork,
+		setupPlacementGroup,
+		setupServer,
+		setupServerPool,
+		setupVolume,
+	} {
+		if err := setup(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A change to a field between the old and new versions of a resource
+type change struct {
+	path     *field.Path
+	old, new any
+}
+
+// immutable warns about each field that was changed. The fields are only
+// read by Create, so once the external resource exists a change has no
+// effect.
+func immutable(created bool, changes ...change) admission.Warnings {
+	if !created {
+		return nil
+	}
+
+	var warnings admission.Warnings
+	for _, c := range changes {
+		if !reflect.DeepEqual(c.old, c.new) {
+			warnings = append(warnings, fmt.Sprintf("%s cannot be changed once the resource is created and will be ignored", c.path))
+		}
+	}
+
+	return warnings
+}
+
+// unvalidated reports whether an update is admitted without validating the
+// resource. A resource being deleted must be able to have its finalizers
+// removed, and an update that leaves the spec alone can't make it any less
+// valid, so neither gets stuck on rules added after the resource was created.
+func unvalidated(cr metav1.Object, oldSpec, newSpec any) bool {
+	return cr.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(oldSpec, newSpec)
+}
+
+// A replaceable resource is deleted and recreated when its create-only
+// fields change
+type replaceable interface {
+	NeedsReplacement() bool
+}
+
+// replacing warns when an update will cause the Hetzner resource to be
+// replaced. Changes to create-only fields are otherwise rejected by the CRD's
+// validation rules.
+func replacing(cr replaceable) admission.Warnings {
+	if !cr.NeedsReplacement() {
+		return nil
+	}
+
+	return admission.Warnings{"create-only fields have changed, so the Hetzner resource will be deleted and recreated"}
+}
+
+// validateCIDR parses a CIDR, returning a field error if it is malformed
+func validateCIDR(path *field.Path, cidr string) (*net.IPNet, *field.Error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, field.Invalid(path, cidr, "must be a CIDR, such as 10.0.0.0/16")
+	}
+
+	return ipNet, nil
+}
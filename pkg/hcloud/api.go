@@ -0,0 +1,123 @@
+package hcloud
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// The interfaces below are the parts of the hcloud-go client used by the
+// provider. Each is satisfied by the matching hcloud-go client, so they can
+// be swapped for mocks or decorated without changing the controllers.
+
+// ActionAPI reads the progress of asynchronous actions
+type ActionAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.Action, *hcloud.Response, error)
+}
+
+// DatacenterAPI looks up datacenters
+type DatacenterAPI interface {
+	GetByName(ctx context.Context, name string) (*hcloud.Datacenter, *hcloud.Response, error)
+}
+
+// FirewallAPI manages firewalls, their rules and the resources they are
+// applied to
+type FirewallAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.Firewall, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.FirewallCreateOpts) (hcloud.FirewallCreateResult, *hcloud.Response, error)
+	Update(ctx context.Context, firewall *hcloud.Firewall, opts hcloud.FirewallUpdateOpts) (*hcloud.Firewall, *hcloud.Response, error)
+	Delete(ctx context.Context, firewall *hcloud.Firewall) (*hcloud.Response, error)
+	SetRules(ctx context.Context, firewall *hcloud.Firewall, opts hcloud.FirewallSetRulesOpts) ([]*hcloud.Action, *hcloud.Response, error)
+	ApplyResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) ([]*hcloud.Action, *hcloud.Response, error)
+	RemoveResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) ([]*hcloud.Action, *hcloud.Response, error)
+}
+
+// ImageAPI looks up images
+type ImageAPI interface {
+	GetByNameAndArchitecture(ctx context.Context, name string, architecture hcloud.Architecture) (*hcloud.Image, *hcloud.Response, error)
+}
+
+// ISOAPI looks up ISOs which can be attached to servers
+type ISOAPI interface {
+	Get(ctx context.Context, idOrName string) (*hcloud.ISO, *hcloud.Response, error)
+}
+
+// LocationAPI looks up locations
+type LocationAPI interface {
+	GetByName(ctx context.Context, name string) (*hcloud.Location, *hcloud.Response, error)
+}
+
+// NetworkAPI manages private networks
+type NetworkAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.Network, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.NetworkCreateOpts) (*hcloud.Network, *hcloud.Response, error)
+	Update(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkUpdateOpts) (*hcloud.Network, *hcloud.Response, error)
+	Delete(ctx context.Context, network *hcloud.Network) (*hcloud.Response, error)
+	ChangeIPRange(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkChangeIPRangeOpts) (*hcloud.Action, *hcloud.Response, error)
+}
+
+// PlacementGroupAPI manages placement groups
+type PlacementGroupAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.PlacementGroup, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.PlacementGroupCreateOpts) (hcloud.PlacementGroupCreateResult, *hcloud.Response, error)
+	Update(ctx context.Context, placementGroup *hcloud.PlacementGroup, opts hcloud.PlacementGroupUpdateOpts) (*hcloud.PlacementGroup, *hcloud.Response, error)
+	Delete(ctx context.Context, placementGroup *hcloud.PlacementGroup) (*hcloud.Response, error)
+}
+
+// ServerAPI manages servers and their power state
+type ServerAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.Server, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error)
+	Update(ctx context.Context, server *hcloud.Server, opts hcloud.ServerUpdateOpts) (*hcloud.Server, *hcloud.Response, error)
+	DeleteWithResult(ctx context.Context, server *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error)
+	Poweron(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	Poweroff(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	Shutdown(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	Reboot(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	Reset(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	ResetPassword(ctx context.Context, server *hcloud.Server) (hcloud.ServerResetPasswordResult, *hcloud.Response, error)
+	AttachISO(ctx context.Context, server *hcloud.Server, iso *hcloud.ISO) (*hcloud.Action, *hcloud.Response, error)
+	DetachISO(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+	AttachToNetwork(ctx context.Context, server *hcloud.Server, opts hcloud.ServerAttachToNetworkOpts) (*hcloud.Action, *hcloud.Response, error)
+	DetachFromNetwork(ctx context.Context, server *hcloud.Server, opts hcloud.ServerDetachFromNetworkOpts) (*hcloud.Action, *hcloud.Response, error)
+	AddToPlacementGroup(ctx context.Context, server *hcloud.Server, placementGroup *hcloud.PlacementGroup) (*hcloud.Action, *hcloud.Response, error)
+	RemoveFromPlacementGroup(ctx context.Context, server *hcloud.Server) (*hcloud.Action, *hcloud.Response, error)
+}
+
+// ServerTypeAPI looks up server types
+type ServerTypeAPI interface {
+	GetByName(ctx context.Context, name string) (*hcloud.ServerType, *hcloud.Response, error)
+}
+
+// SSHKeyAPI manages the SSH keys stored in a project
+type SSHKeyAPI interface {
+	GetByFingerprint(ctx context.Context, fingerprint string) (*hcloud.SSHKey, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.SSHKeyCreateOpts) (*hcloud.SSHKey, *hcloud.Response, error)
+	Delete(ctx context.Context, sshKey *hcloud.SSHKey) (*hcloud.Response, error)
+}
+
+// VolumeAPI manages volumes and their attachments
+type VolumeAPI interface {
+	GetByID(ctx context.Context, id int64) (*hcloud.Volume, *hcloud.Response, error)
+	Create(ctx context.Context, opts hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error)
+	Update(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeUpdateOpts) (*hcloud.Volume, *hcloud.Response, error)
+	Delete(ctx context.Context, volume *hcloud.Volume) (*hcloud.Response, error)
+	AttachWithOpts(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) (*hcloud.Action, *hcloud.Response, error)
+	Detach(ctx context.Context, volume *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error)
+	Resize(ctx context.Context, volume *hcloud.Volume, size int) (*hcloud.Action, *hcloud.Response, error)
+}
+
+var (
+	_ ActionAPI         = &hcloud.ActionClient{}
+	_ DatacenterAPI     = &hcloud.DatacenterClient{}
+	_ FirewallAPI       = &hcloud.FirewallClient{}
+	_ ImageAPI          = &hcloud.ImageClient{}
+	_ ISOAPI            = &hcloud.ISOClient{}
+	_ LocationAPI       = &hcloud.LocationClient{}
+	_ NetworkAPI        = &hcloud.NetworkClient{}
+	_ PlacementGroupAPI = &hcloud.PlacementGroupClient{}
+	_ ServerAPI         = &hcloud.ServerClient{}
+	_ ServerTypeAPI     = &hcloud.ServerTypeClient{}
+	_ SSHKeyAPI         = &hcloud.SSHKeyClient{}
+	_ VolumeAPI         = &hcloud.VolumeClient{}
+)
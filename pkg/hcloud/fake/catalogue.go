@@ -0,0 +1,208 @@
+package fake
+
+import (
+	"net/http"
+	"slices"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// seedCatalogue adds the read-only resources a project can choose from
+func (a *API) seedCatalogue() {
+	a.locations = []schema.Location{
+		{ID: 1, Name: "fsn1", Description: "Falkenstein DC Park 1", Country: "DE", City: "Falkenstein", NetworkZone: "eu-central"},
+		{ID: 2, Name: "nbg1", Description: "Nuremberg DC Park 1", Country: "DE", City: "Nuremberg", NetworkZone: "eu-central"},
+		{ID: 3, Name: "hel1", Description: "Helsinki DC Park 1", Country: "FI", City: "Helsinki", NetworkZone: "eu-central"},
+		{ID: 4, Name: "ash", Description: "Ashburn, VA", Country: "US", City: "Ashburn, VA", NetworkZone: "us-east"},
+	}
+
+	a.datacenters = []schema.Datacenter{
+		{ID: 4, Name: "fsn1-dc14", Description: "Falkenstein 1 virtual DC 14", Location: a.locations[0]},
+		{ID: 2, Name: "nbg1-dc3", Description: "Nuremberg 1 virtual DC 3", Location: a.locations[1]},
+		{ID: 3, Name: "hel1-dc2", Description: "Helsinki 1 virtual DC 2", Location: a.locations[2]},
+		{ID: 5, Name: "ash-dc1", Description: "Ashburn virtual DC 1", Location: a.locations[3]},
+	}
+
+	a.serverTypes = []schema.ServerType{
+		{ID: 22, Name: "cx22", Description: "CX22", Cores: 2, Memory: 4, Disk: 40, StorageType: "local", CPUType: "shared", Architecture: string(hcloudsdk.ArchitectureX86)},
+		{ID: 23, Name: "cpx11", Description: "CPX 11", Cores: 2, Memory: 2, Disk: 40, StorageType: "local", CPUType: "shared", Architecture: string(hcloudsdk.ArchitectureX86)},
+		{ID: 45, Name: "cax11", Description: "CAX11", Cores: 2, Memory: 4, Disk: 40, StorageType: "local", CPUType: "shared", Architecture: string(hcloudsdk.ArchitectureARM)},
+	}
+
+	a.images = []schema.Image{
+		systemImage(67794396, "ubuntu-22.04", "ubuntu", "22.04", hcloudsdk.ArchitectureX86),
+		systemImage(103908070, "ubuntu-22.04", "ubuntu", "22.04", hcloudsdk.ArchitectureARM),
+		systemImage(114690387, "debian-12", "debian", "12", hcloudsdk.ArchitectureX86),
+		systemImage(114690389, "debian-12", "debian", "12", hcloudsdk.ArchitectureARM),
+	}
+
+	a.isos = []schema.ISO{
+		publicISO(9032, "ubuntu-24.04-live-server-amd64.iso", "Ubuntu 24.04 (amd64)", hcloudsdk.ArchitectureX86),
+		publicISO(9033, "ubuntu-24.04-live-server-arm64.iso", "Ubuntu 24.04 (arm64)", hcloudsdk.ArchitectureARM),
+	}
+}
+
+func systemImage(id int64, name, flavor, version string, architecture hcloudsdk.Architecture) schema.Image {
+	return schema.Image{
+		ID:           id,
+		Status:       string(hcloudsdk.ImageStatusAvailable),
+		Type:         string(hcloudsdk.ImageTypeSystem),
+		Name:         &name,
+		Description:  name,
+		DiskSize:     5,
+		OSFlavor:     flavor,
+		OSVersion:    &version,
+		Architecture: string(architecture),
+		Labels:       map[string]string{},
+	}
+}
+
+func publicISO(id int64, name, description string, architecture hcloudsdk.Architecture) schema.ISO {
+	arch := string(architecture)
+
+	return schema.ISO{
+		ID:           id,
+		Name:         name,
+		Description:  description,
+		Type:         string(hcloudsdk.ISOTypePublic),
+		Architecture: &arch,
+	}
+}
+
+// handleCatalogue serves a read-only resource which can be listed by name
+// or fetched by ID
+func handleCatalogue[T any](w http.ResponseWriter, r *http.Request, path []string, key string, items []T, ref func(T) (int64, string)) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	if len(path) == 0 {
+		name := r.URL.Query().Get("name")
+
+		found := make([]T, 0)
+		for _, item := range items {
+			if _, n := ref(item); name == "" || n == name {
+				found = append(found, item)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{key + "s": found})
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	for _, item := range items {
+		if i, _ := ref(item); i == id {
+			writeJSON(w, http.StatusOK, map[string]any{key: item})
+			return
+		}
+	}
+
+	notFound(w)
+}
+
+func (a *API) handleImages(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) > 0 || r.Method != http.MethodGet {
+		handleCatalogue(w, r, path, "image", a.images, func(i schema.Image) (int64, string) {
+			return i.ID, *i.Name
+		})
+		return
+	}
+
+	query := r.URL.Query()
+
+	images := make([]schema.Image, 0)
+	for _, image := range a.images {
+		if name := query.Get("name"); name != "" && *image.Name != name {
+			continue
+		}
+		if architectures := query["architecture"]; len(architectures) > 0 && !slices.Contains(architectures, image.Architecture) {
+			continue
+		}
+
+		images = append(images, image)
+	}
+
+	writeJSON(w, http.StatusOK, schema.ImageListResponse{Images: images})
+}
+
+func (a *API) handleISOs(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) > 0 || r.Method != http.MethodGet {
+		handleCatalogue(w, r, path, "iso", a.isos, func(i schema.ISO) (int64, string) {
+			return i.ID, i.Name
+		})
+		return
+	}
+
+	query := r.URL.Query()
+
+	isos := make([]schema.ISO, 0)
+	for _, iso := range a.isos {
+		if name := query.Get("name"); name != "" && iso.Name != name {
+			continue
+		}
+		if architectures := query["architecture"]; len(architectures) > 0 && iso.Architecture != nil && !slices.Contains(architectures, *iso.Architecture) {
+			continue
+		}
+
+		isos = append(isos, iso)
+	}
+
+	writeJSON(w, http.StatusOK, schema.ISOListResponse{ISOs: isos})
+}
+
+func (a *API) findServerType(ref any) (schema.ServerType, bool) {
+	for _, serverType := range a.serverTypes {
+		if matchesIDOrName(ref, serverType.ID, serverType.Name) {
+			return serverType, true
+		}
+	}
+
+	return schema.ServerType{}, false
+}
+
+func (a *API) findImage(ref any, architecture string) (schema.Image, bool) {
+	for _, image := range a.images {
+		if matchesIDOrName(ref, image.ID, *image.Name) && (architecture == "" || image.Architecture == architecture) {
+			return image, true
+		}
+	}
+
+	return schema.Image{}, false
+}
+
+func (a *API) findISO(ref any) (schema.ISO, bool) {
+	for _, iso := range a.isos {
+		if matchesIDOrName(ref, iso.ID, iso.Name) {
+			return iso, true
+		}
+	}
+
+	return schema.ISO{}, false
+}
+
+func (a *API) findDatacenter(ref any) (schema.Datacenter, bool) {
+	for _, datacenter := range a.datacenters {
+		if matchesIDOrName(ref, datacenter.ID, datacenter.Name) {
+			return datacenter, true
+		}
+	}
+
+	return schema.Datacenter{}, false
+}
+
+func (a *API) findLocation(ref any) (schema.Location, bool) {
+	for _, location := range a.locations {
+		if matchesIDOrName(ref, location.ID, location.Name) {
+			return location, true
+		}
+	}
+
+	return schema.Location{}, false
+}
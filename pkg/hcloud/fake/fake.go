@@ -0,0 +1,411 @@
+// Package fake provides an in-memory stand-in for the Hetzner Cloud API so
+// the controllers can be tested end-to-end without network access.
+//
+// Only the endpoints used by the provider are implemented. Actions complete
+// as soon as they are created, unless told to fail with FailActions.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+)
+
+// Failure makes matching requests fail with an API error
+type Failure struct {
+	// Method to match, such as "POST". Matches every method if empty.
+	Method string
+
+	// Path to match, such as "/servers/1". A trailing "*" matches by prefix.
+	Path string
+
+	// StatusCode of the response. Defaults to 500.
+	StatusCode int
+
+	// Code and Message make up the error body. Avoid the codes the hcloud-go
+	// client retries, such as "conflict" and "rate_limit_exceeded", unless
+	// that is being tested.
+	Code    hcloudsdk.ErrorCode
+	Message string
+
+	// Times limits how many requests fail. Fails every request if zero.
+	Times int
+}
+
+func (f *Failure) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+
+	if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+
+	return f.Path == r.URL.Path
+}
+
+// API is a fake Hetzner Cloud API served over HTTP
+type API struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	nextID int64
+
+	actions         map[int64]*schema.Action
+	firewalls       map[int64]*schema.Firewall
+	networks        map[int64]*schema.Network
+	placementGroups map[int64]*schema.PlacementGroup
+	servers         map[int64]*schema.Server
+	sshKeys         map[int64]*schema.SSHKey
+	volumes         map[int64]*schema.Volume
+
+	datacenters []schema.Datacenter
+	images      []schema.Image
+	isos        []schema.ISO
+	locations   []schema.Location
+	serverTypes []schema.ServerType
+
+	failures    []*Failure
+	actionError *schema.ActionError
+	requests    []string
+
+	rateLimitRemaining int
+}
+
+// RateLimit is the request budget reported in the RateLimit-Limit header.
+// The budget is spent by every request but never enforced.
+const RateLimit = 3600
+
+// NewAPI starts a fake API seeded with a catalogue of locations,
+// datacenters, images and server types. Call Close when finished.
+func NewAPI() *API {
+	a := &API{
+		nextID:          1000,
+		actions:         map[int64]*schema.Action{},
+		firewalls:       map[int64]*schema.Firewall{},
+		networks:        map[int64]*schema.Network{},
+		placementGroups: map[int64]*schema.PlacementGroup{},
+		servers:         map[int64]*schema.Server{},
+		sshKeys:         map[int64]*schema.SSHKey{},
+		volumes:         map[int64]*schema.Volume{},
+
+		rateLimitRemaining: RateLimit,
+	}
+
+	a.seedCatalogue()
+	a.server = httptest.NewServer(a)
+
+	return a
+}
+
+// URL is the endpoint of the fake API
+func (a *API) URL() string {
+	return a.server.URL
+}
+
+// Close shuts the fake API down
+func (a *API) Close() {
+	a.server.Close()
+}
+
+// ClientOptions connect a Client to the fake API. Actions are polled
+// without delay and failed requests are not retried.
+func (a *API) ClientOptions() []hcloud.Option {
+	return []hcloud.Option{
+		hcloud.WithPollInterval(time.Millisecond),
+		hcloud.WithClientOptions(
+			hcloudsdk.WithEndpoint(a.URL()),
+			hcloudsdk.WithPollInterval(time.Millisecond),
+			hcloudsdk.WithRetryOpts(hcloudsdk.RetryOpts{
+				BackoffFunc: hcloudsdk.ConstantBackoff(0),
+				MaxRetries:  0,
+			}),
+		),
+	}
+}
+
+// Client returns a Client connected to the fake API with ClientOptions
+func (a *API) Client(opts ...hcloud.Option) *hcloud.Client {
+	opts = append(a.ClientOptions(), opts...)
+
+	c, err := hcloud.NewClient("fake-token", opts...)
+	if err != nil {
+		// NewClient does not fail for a static token
+		panic(err)
+	}
+
+	return c
+}
+
+// Fail makes matching requests fail until the failure is used up
+func (a *API) Fail(f Failure) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusInternalServerError
+	}
+
+	a.failures = append(a.failures, &f)
+}
+
+// FailActions makes every action created from now on finish with the given
+// error. An empty code makes actions succeed again.
+func (a *API) FailActions(code, message string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if code == "" {
+		a.actionError = nil
+		return
+	}
+
+	a.actionError = &schema.ActionError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Requests lists every request received, as "METHOD /path"
+func (a *API) Requests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]string{}, a.requests...)
+}
+
+// ServeHTTP routes a request to the handler for its resource
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+
+	if a.rateLimitRemaining > 0 {
+		a.rateLimitRemaining--
+	}
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(RateLimit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(a.rateLimitRemaining))
+	w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+	if f := a.failure(r); f != nil {
+		writeError(w, f.StatusCode, f.Code, f.Message)
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	// Each resource has its own copy of the actions endpoint
+	if len(path) == 3 && path[1] == "actions" {
+		path = path[1:]
+	}
+
+	switch path[0] {
+	case "actions":
+		a.handleActions(w, r, path[1:])
+	case "datacenters":
+		handleCatalogue(w, r, path[1:], "datacenter", a.datacenters, func(d schema.Datacenter) (int64, string) {
+			return d.ID, d.Name
+		})
+	case "firewalls":
+		a.handleFirewalls(w, r, path[1:])
+	case "images":
+		a.handleImages(w, r, path[1:])
+	case "isos":
+		a.handleISOs(w, r, path[1:])
+	case "locations":
+		handleCatalogue(w, r, path[1:], "location", a.locations, func(l schema.Location) (int64, string) {
+			return l.ID, l.Name
+		})
+	case "networks":
+		a.handleNetworks(w, r, path[1:])
+	case "placement_groups":
+		a.handlePlacementGroups(w, r, path[1:])
+	case "server_types":
+		handleCatalogue(w, r, path[1:], "server_type", a.serverTypes, func(s schema.ServerType) (int64, string) {
+			return s.ID, s.Name
+		})
+	case "servers":
+		a.handleServers(w, r, path[1:])
+	case "ssh_keys":
+		a.handleSSHKeys(w, r, path[1:])
+	case "volumes":
+		a.handleVolumes(w, r, path[1:])
+	default:
+		notFound(w)
+	}
+}
+
+func (a *API) failure(r *http.Request) *Failure {
+	for i, f := range a.failures {
+		if !f.matches(r) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				a.failures = append(a.failures[:i], a.failures[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+func (a *API) newID() int64 {
+	a.nextID++
+	return a.nextID
+}
+
+// newAction records a finished action against the given resources
+func (a *API) newAction(command, resourceType string, ids ...int64) schema.Action {
+	now := time.Now()
+
+	action := &schema.Action{
+		ID:        a.newID(),
+		Status:    string(hcloudsdk.ActionStatusSuccess),
+		Command:   command,
+		Progress:  100,
+		Started:   now,
+		Finished:  &now,
+		Resources: []schema.ActionResourceReference{},
+	}
+
+	if a.actionError != nil {
+		action.Status = string(hcloudsdk.ActionStatusError)
+		action.Error = &schema.ActionError{
+			Code:    a.actionError.Code,
+			Message: a.actionError.Message,
+		}
+	}
+
+	for _, id := range ids {
+		action.Resources = append(action.Resources, schema.ActionResourceReference{
+			ID:   id,
+			Type: resourceType,
+		})
+	}
+
+	a.actions[action.ID] = action
+
+	return *action
+}
+
+func (a *API) handleActions(w http.ResponseWriter, r *http.Request, path []string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	if len(path) == 0 {
+		actions := make([]schema.Action, 0)
+		for _, v := range r.URL.Query()["id"] {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				invalidInput(w, "invalid action id")
+				return
+			}
+			if action, ok := a.actions[id]; ok {
+				actions = append(actions, *action)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, schema.ActionListResponse{Actions: actions})
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	action, ok := a.actions[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema.ActionGetResponse{Action: *action})
+}
+
+// decode reads the request body into v, writing an error response if it
+// cannot be parsed
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, hcloudsdk.ErrorCodeJSONError, err.Error())
+		return false
+	}
+
+	return true
+}
+
+func parseID(w http.ResponseWriter, s string) (int64, bool) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		notFound(w)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// matchesIDOrName checks a reference which may be sent as an ID or a name.
+// JSON numbers arrive as float64.
+func matchesIDOrName(ref any, id int64, name string) bool {
+	switch v := ref.(type) {
+	case float64:
+		return int64(v) == id
+	case string:
+		return v == name || v == strconv.FormatInt(id, 10)
+	}
+
+	return false
+}
+
+func labelsOrEmpty(labels *map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+
+	return *labels
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code hcloudsdk.ErrorCode, message string) {
+	writeJSON(w, status, schema.ErrorResponse{
+		Error: schema.Error{
+			Code:    string(code),
+			Message: message,
+		},
+	})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, hcloudsdk.ErrorCodeNotFound, "not found")
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, hcloudsdk.ErrorCodeInvalidInput, "method not allowed")
+}
+
+func invalidInput(w http.ResponseWriter, format string, args ...any) {
+	writeError(w, http.StatusBadRequest, hcloudsdk.ErrorCodeInvalidInput, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestMatchesSelector(t *testing.T) {
+	labels := map[string]string{
+		"environment": "prod",
+		"role":        "web",
+	}
+
+	cases := map[string]struct {
+		selector string
+		want     bool
+	}{
+		"Empty":          {selector: "", want: true},
+		"Equals":         {selector: "environment=prod", want: true},
+		"DoubleEquals":   {selector: "environment==staging", want: false},
+		"NotEquals":      {selector: "environment!=staging", want: true},
+		"In":             {selector: "role in (api,web)", want: true},
+		"NotIn":          {selector: "role notin (api,web)", want: false},
+		"Exists":         {selector: "role", want: true},
+		"DoesNotExist":   {selector: "!deprecated", want: true},
+		"AllMustMatch":   {selector: "environment==prod,role in (api),!deprecated", want: false},
+		"SetsWithCommas": {selector: "environment in (prod,staging),role==web", want: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := matchesSelector(labels, tc.selector); got != tc.want {
+				t.Errorf("matchesSelector(%q): want %t, got %t", tc.selector, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFailure(t *testing.T) {
+	api := NewAPI()
+	defer api.Close()
+
+	server := api.AddServer(schema.Server{Name: "example"})
+
+	api.Fail(Failure{
+		Method:     http.MethodGet,
+		Path:       "/servers/*",
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       hcloudsdk.ErrorCodeMaintenance,
+		Message:    "down for maintenance",
+		Times:      1,
+	})
+
+	c := api.Client()
+
+	_, _, err := c.Server.GetByID(context.Background(), server.ID)
+	if !hcloudsdk.IsError(err, hcloudsdk.ErrorCodeMaintenance) {
+		t.Fatalf("first request: want maintenance error, got %v", err)
+	}
+
+	got, _, err := c.Server.GetByID(context.Background(), server.ID)
+	if err != nil {
+		t.Fatalf("second request: want no error, got %v", err)
+	}
+	if diff := cmp.Diff(server.Name, got.Name); diff != "" {
+		t.Errorf("second request: -want, +got:\n%s", diff)
+	}
+}
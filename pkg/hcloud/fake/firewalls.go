@@ -0,0 +1,300 @@
+package fake
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// AddFirewall seeds a firewall, assigning an ID if unset. The stored
+// firewall is returned.
+func (a *API) AddFirewall(firewall schema.Firewall) schema.Firewall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if firewall.ID == 0 {
+		firewall.ID = a.newID()
+	}
+	if firewall.Created.IsZero() {
+		firewall.Created = time.Now()
+	}
+	if firewall.Labels == nil {
+		firewall.Labels = map[string]string{}
+	}
+	if firewall.Rules == nil {
+		firewall.Rules = []schema.FirewallRule{}
+	}
+	if firewall.AppliedTo == nil {
+		firewall.AppliedTo = []schema.FirewallResource{}
+	}
+
+	a.firewalls[firewall.ID] = &firewall
+	a.syncServerFirewalls()
+
+	return firewall
+}
+
+// Firewall returns the stored firewall with the given ID
+func (a *API) Firewall(id int64) (schema.Firewall, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	firewall, ok := a.firewalls[id]
+	if !ok {
+		return schema.Firewall{}, false
+	}
+
+	return *firewall, true
+}
+
+func (a *API) handleFirewalls(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+
+			firewalls := make([]schema.Firewall, 0)
+			for _, id := range sortedIDs(a.firewalls) {
+				firewall := a.firewalls[id]
+				if name := query.Get("name"); name != "" && firewall.Name != name {
+					continue
+				}
+				if !matchesSelector(firewall.Labels, query.Get("label_selector")) {
+					continue
+				}
+				firewalls = append(firewalls, *firewall)
+			}
+
+			writeJSON(w, http.StatusOK, schema.FirewallListResponse{Firewalls: firewalls})
+		case http.MethodPost:
+			a.createFirewall(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	firewall, ok := a.firewalls[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(path) == 3 && path[1] == "actions" && r.Method == http.MethodPost {
+		a.firewallAction(w, r, firewall, path[2])
+		return
+	}
+	if len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.FirewallGetResponse{Firewall: *firewall})
+	case http.MethodPut:
+		var req schema.FirewallUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			firewall.Name = *req.Name
+		}
+		if req.Labels != nil {
+			firewall.Labels = *req.Labels
+		}
+		writeJSON(w, http.StatusOK, schema.FirewallUpdateResponse{Firewall: *firewall})
+	case http.MethodDelete:
+		if len(firewall.AppliedTo) > 0 {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeResourceInUse, "firewall is still applied to resources")
+			return
+		}
+		delete(a.firewalls, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) createFirewall(w http.ResponseWriter, r *http.Request) {
+	var req schema.FirewallCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	for _, firewall := range a.firewalls {
+		if firewall.Name == req.Name {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeUniquenessError, "firewall name is already used")
+			return
+		}
+	}
+
+	firewall := &schema.Firewall{
+		ID:        a.newID(),
+		Name:      req.Name,
+		Labels:    labelsOrEmpty(req.Labels),
+		Created:   time.Now(),
+		Rules:     firewallRules(req.Rules),
+		AppliedTo: []schema.FirewallResource{},
+	}
+
+	actions := make([]schema.Action, 0)
+	for _, resource := range req.ApplyTo {
+		if !a.validFirewallResource(w, resource) {
+			return
+		}
+		firewall.AppliedTo = append(firewall.AppliedTo, resource)
+		actions = append(actions, a.newAction("apply_firewall", "firewall", firewall.ID))
+	}
+
+	a.firewalls[firewall.ID] = firewall
+	a.syncServerFirewalls()
+
+	writeJSON(w, http.StatusCreated, schema.FirewallCreateResponse{
+		Firewall: *firewall,
+		Actions:  actions,
+	})
+}
+
+func (a *API) firewallAction(w http.ResponseWriter, r *http.Request, firewall *schema.Firewall, command string) { //nolint:gocyclo
+	actions := make([]schema.Action, 0)
+
+	switch command {
+	case "set_rules":
+		var req schema.FirewallActionSetRulesRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		firewall.Rules = firewallRules(req.Rules)
+		actions = append(actions, a.newAction(command, "firewall", firewall.ID))
+	case "apply_to_resources":
+		var req schema.FirewallActionApplyToResourcesRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		for _, resource := range req.ApplyTo {
+			if !a.validFirewallResource(w, resource) {
+				return
+			}
+			if slices.ContainsFunc(firewall.AppliedTo, sameFirewallResource(resource)) {
+				writeError(w, http.StatusUnprocessableEntity, hcloudsdk.ErrorCodeFirewallAlreadyApplied, "firewall is already applied to the resource")
+				return
+			}
+		}
+		for _, resource := range req.ApplyTo {
+			firewall.AppliedTo = append(firewall.AppliedTo, resource)
+			actions = append(actions, a.newAction("apply_firewall", "firewall", firewall.ID))
+		}
+	case "remove_from_resources":
+		var req schema.FirewallActionRemoveFromResourcesRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		for _, resource := range req.RemoveFrom {
+			if !slices.ContainsFunc(firewall.AppliedTo, sameFirewallResource(resource)) {
+				writeError(w, http.StatusUnprocessableEntity, hcloudsdk.ErrorCodeFirewallResourceNotFound, "firewall is not applied to the resource")
+				return
+			}
+		}
+		for _, resource := range req.RemoveFrom {
+			firewall.AppliedTo = slices.DeleteFunc(firewall.AppliedTo, sameFirewallResource(resource))
+			actions = append(actions, a.newAction("remove_firewall", "firewall", firewall.ID))
+		}
+	default:
+		notFound(w)
+		return
+	}
+
+	a.syncServerFirewalls()
+
+	writeJSON(w, http.StatusCreated, schema.FirewallActionSetRulesResponse{Actions: actions})
+}
+
+func (a *API) validFirewallResource(w http.ResponseWriter, resource schema.FirewallResource) bool {
+	switch hcloudsdk.FirewallResourceType(resource.Type) {
+	case hcloudsdk.FirewallResourceTypeServer:
+		if resource.Server == nil {
+			invalidInput(w, "server resource requires a server")
+			return false
+		}
+		if _, ok := a.servers[resource.Server.ID]; !ok {
+			writeError(w, http.StatusNotFound, hcloudsdk.ErrorCodeFirewallResourceNotFound, "server not found")
+			return false
+		}
+	case hcloudsdk.FirewallResourceTypeLabelSelector:
+		if resource.LabelSelector == nil || resource.LabelSelector.Selector == "" {
+			invalidInput(w, "label_selector resource requires a selector")
+			return false
+		}
+	default:
+		invalidInput(w, "unknown resource type %s", resource.Type)
+		return false
+	}
+
+	return true
+}
+
+// syncServerFirewalls sets the firewalls on each server's public network
+// from the resources the firewalls are applied to
+func (a *API) syncServerFirewalls() {
+	for _, server := range a.servers {
+		server.PublicNet.Firewalls = []schema.ServerFirewall{}
+
+		for _, id := range sortedIDs(a.firewalls) {
+			firewall := a.firewalls[id]
+
+			applied := slices.ContainsFunc(firewall.AppliedTo, func(r schema.FirewallResource) bool {
+				if r.Server != nil {
+					return r.Server.ID == server.ID
+				}
+				return r.LabelSelector != nil && matchesSelector(server.Labels, r.LabelSelector.Selector)
+			})
+
+			if applied {
+				server.PublicNet.Firewalls = append(server.PublicNet.Firewalls, schema.ServerFirewall{
+					ID:     firewall.ID,
+					Status: string(hcloudsdk.FirewallStatusApplied),
+				})
+			}
+		}
+	}
+}
+
+func sameFirewallResource(resource schema.FirewallResource) func(schema.FirewallResource) bool {
+	return func(r schema.FirewallResource) bool {
+		if r.Type != resource.Type {
+			return false
+		}
+		if r.Server != nil && resource.Server != nil {
+			return r.Server.ID == resource.Server.ID
+		}
+		if r.LabelSelector != nil && resource.LabelSelector != nil {
+			return r.LabelSelector.Selector == resource.LabelSelector.Selector
+		}
+		return false
+	}
+}
+
+func firewallRules(rules []schema.FirewallRuleRequest) []schema.FirewallRule {
+	out := make([]schema.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		out = append(out, schema.FirewallRule{
+			Direction:      rule.Direction,
+			SourceIPs:      append([]string{}, rule.SourceIPs...),
+			DestinationIPs: append([]string{}, rule.DestinationIPs...),
+			Protocol:       rule.Protocol,
+			Port:           rule.Port,
+			Description:    rule.Description,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,60 @@
+package fake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+
+	"github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+)
+
+// The helpers below build the fixtures shared by the controller tests.
+
+// ProviderLabels are the labels the provider puts on every resource. A
+// resource seeded with them has no label drift.
+func ProviderLabels() map[string]string {
+	return map[string]string{hcloud.ProviderLabel: hcloud.Provider}
+}
+
+// Forbid makes every GET of path fail as if the token lacked permission. The
+// error the client returns is given back so tests can compare against it.
+func (a *API) Forbid(path string) error {
+	err := hcloudsdk.Error{
+		Code:    hcloudsdk.ErrorCodeForbidden,
+		Message: "insufficient permissions",
+	}
+
+	a.Fail(Failure{
+		Method:     http.MethodGet,
+		Path:       path,
+		StatusCode: http.StatusForbidden,
+		Code:       err.Code,
+		Message:    err.Message,
+	})
+
+	return err
+}
+
+// ServerResource is a firewall's reference to the server with the given ID
+func ServerResource(id int64) schema.FirewallResource {
+	return schema.FirewallResource{
+		Type:   string(hcloudsdk.FirewallResourceTypeServer),
+		Server: &schema.FirewallResourceServer{ID: id},
+	}
+}
+
+// GetServer reads a server through the client, as a controller would see it,
+// failing the test if it cannot be read
+func GetServer(t testing.TB, c *hcloud.Client, id int64) *hcloudsdk.Server {
+	t.Helper()
+
+	server, _, err := c.Server.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server
+}
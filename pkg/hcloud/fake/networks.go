@@ -0,0 +1,273 @@
+package fake
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// AddNetwork seeds a network, assigning an ID if unset. The stored network
+// is returned.
+func (a *API) AddNetwork(network schema.Network) schema.Network {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if network.ID == 0 {
+		network.ID = a.newID()
+	}
+	if network.Created.IsZero() {
+		network.Created = time.Now()
+	}
+	if network.Labels == nil {
+		network.Labels = map[string]string{}
+	}
+
+	a.networks[network.ID] = &network
+
+	return network
+}
+
+// Network returns the stored network with the given ID
+func (a *API) Network(id int64) (schema.Network, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	network, ok := a.networks[id]
+	if !ok {
+		return schema.Network{}, false
+	}
+
+	return *network, true
+}
+
+func (a *API) handleNetworks(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+
+			networks := make([]schema.Network, 0)
+			for _, id := range sortedIDs(a.networks) {
+				network := a.networks[id]
+				if name := query.Get("name"); name != "" && network.Name != name {
+					continue
+				}
+				if !matchesSelector(network.Labels, query.Get("label_selector")) {
+					continue
+				}
+				networks = append(networks, *network)
+			}
+
+			writeJSON(w, http.StatusOK, schema.NetworkListResponse{Networks: networks})
+		case http.MethodPost:
+			a.createNetwork(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	network, ok := a.networks[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(path) == 3 && path[1] == "actions" && r.Method == http.MethodPost {
+		a.networkAction(w, r, network, path[2])
+		return
+	}
+	if len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.NetworkGetResponse{Network: *network})
+	case http.MethodPut:
+		var req schema.NetworkUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != "" {
+			network.Name = req.Name
+		}
+		if req.Labels != nil {
+			network.Labels = *req.Labels
+		}
+		if req.ExposeRoutesToVSwitch != nil {
+			network.ExposeRoutesToVSwitch = *req.ExposeRoutesToVSwitch
+		}
+		writeJSON(w, http.StatusOK, schema.NetworkUpdateResponse{Network: *network})
+	case http.MethodDelete:
+		if len(network.Servers) > 0 {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeResourceInUse, "network has attached servers")
+			return
+		}
+		delete(a.networks, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) createNetwork(w http.ResponseWriter, r *http.Request) {
+	var req schema.NetworkCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if _, _, err := net.ParseCIDR(req.IPRange); err != nil {
+		invalidInput(w, "invalid ip range %s", req.IPRange)
+		return
+	}
+
+	network := &schema.Network{
+		ID:                    a.newID(),
+		Name:                  req.Name,
+		Created:               time.Now(),
+		IPRange:               req.IPRange,
+		Subnets:               []schema.NetworkSubnet{},
+		Routes:                []schema.NetworkRoute{},
+		Servers:               []int64{},
+		Labels:                labelsOrEmpty(req.Labels),
+		ExposeRoutesToVSwitch: req.ExposeRoutesToVSwitch,
+	}
+
+	for _, subnet := range req.Subnets {
+		subnet, err := newSubnet(subnet)
+		if err != nil {
+			invalidInput(w, "%s", err)
+			return
+		}
+		network.Subnets = append(network.Subnets, subnet)
+	}
+	network.Routes = append(network.Routes, req.Routes...)
+
+	a.networks[network.ID] = network
+
+	writeJSON(w, http.StatusCreated, schema.NetworkCreateResponse{Network: *network})
+}
+
+func (a *API) networkAction(w http.ResponseWriter, r *http.Request, network *schema.Network, command string) {
+	switch command {
+	case "change_ip_range":
+		var req schema.NetworkActionChangeIPRangeRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if _, _, err := net.ParseCIDR(req.IPRange); err != nil {
+			invalidInput(w, "invalid ip range %s", req.IPRange)
+			return
+		}
+		network.IPRange = req.IPRange
+	case "add_subnet":
+		var req schema.NetworkActionAddSubnetRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		subnet, err := newSubnet(schema.NetworkSubnet{
+			Type:        req.Type,
+			IPRange:     req.IPRange,
+			NetworkZone: req.NetworkZone,
+			VSwitchID:   req.VSwitchID,
+		})
+		if err != nil {
+			invalidInput(w, "%s", err)
+			return
+		}
+		network.Subnets = append(network.Subnets, subnet)
+	case "delete_subnet":
+		var req schema.NetworkActionDeleteSubnetRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		network.Subnets = slices.DeleteFunc(network.Subnets, func(s schema.NetworkSubnet) bool {
+			return s.IPRange == req.IPRange
+		})
+	case "add_route":
+		var req schema.NetworkActionAddRouteRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		network.Routes = append(network.Routes, schema.NetworkRoute{
+			Destination: req.Destination,
+			Gateway:     req.Gateway,
+		})
+	case "delete_route":
+		var req schema.NetworkActionDeleteRouteRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		network.Routes = slices.DeleteFunc(network.Routes, func(route schema.NetworkRoute) bool {
+			return route.Destination == req.Destination && route.Gateway == req.Gateway
+		})
+	default:
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, schema.NetworkActionChangeIPRangeResponse{
+		Action: a.newAction(command, "network", network.ID),
+	})
+}
+
+// attachToNetwork adds the server to the network, giving it the requested IP
+// or the next free one
+func (a *API) attachToNetwork(network *schema.Network, serverID int64, ip *string) schema.ServerPrivateNet {
+	network.Servers = append(network.Servers, serverID)
+
+	address := ""
+	if ip != nil {
+		address = *ip
+	} else if _, ipNet, err := net.ParseCIDR(network.IPRange); err == nil {
+		address = nthIP(ipNet.IP, len(network.Servers)+1).String()
+	}
+
+	return schema.ServerPrivateNet{
+		Network:    network.ID,
+		IP:         address,
+		AliasIPs:   []string{},
+		MACAddress: fmt.Sprintf("86:00:00:%02x:%02x:%02x", byte(serverID>>16), byte(serverID>>8), byte(serverID)),
+	}
+}
+
+// newSubnet validates a subnet and assigns its gateway, which is always the
+// first address of the range
+func newSubnet(subnet schema.NetworkSubnet) (schema.NetworkSubnet, error) {
+	_, ipNet, err := net.ParseCIDR(subnet.IPRange)
+	if err != nil {
+		return subnet, fmt.Errorf("invalid subnet ip range %s", subnet.IPRange)
+	}
+
+	subnet.Gateway = nthIP(ipNet.IP, 1).String()
+
+	return subnet, nil
+}
+
+func nthIP(base net.IP, n int) net.IP {
+	if base.To4() == nil {
+		return base
+	}
+
+	ip := append(net.IP{}, base.To4()...)
+
+	for i := len(ip) - 1; i >= 0 && n > 0; i-- {
+		sum := int(ip[i]) + n
+		ip[i] = byte(sum % 256)
+		n = sum / 256
+	}
+
+	return ip
+}
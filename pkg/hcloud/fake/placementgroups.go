@@ -0,0 +1,134 @@
+package fake
+
+import (
+	"net/http"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// AddPlacementGroup seeds a placement group, assigning an ID and defaults
+// for any unset fields. The stored placement group is returned.
+func (a *API) AddPlacementGroup(placementGroup schema.PlacementGroup) schema.PlacementGroup {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if placementGroup.ID == 0 {
+		placementGroup.ID = a.newID()
+	}
+	if placementGroup.Type == "" {
+		placementGroup.Type = string(hcloudsdk.PlacementGroupTypeSpread)
+	}
+	if placementGroup.Created.IsZero() {
+		placementGroup.Created = time.Now()
+	}
+	if placementGroup.Labels == nil {
+		placementGroup.Labels = map[string]string{}
+	}
+	if placementGroup.Servers == nil {
+		placementGroup.Servers = []int64{}
+	}
+
+	a.placementGroups[placementGroup.ID] = &placementGroup
+
+	return placementGroup
+}
+
+// PlacementGroup returns the stored placement group with the given ID
+func (a *API) PlacementGroup(id int64) (schema.PlacementGroup, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	placementGroup, ok := a.placementGroups[id]
+	if !ok {
+		return schema.PlacementGroup{}, false
+	}
+
+	return *placementGroup, true
+}
+
+func (a *API) handlePlacementGroups(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+
+			placementGroups := make([]schema.PlacementGroup, 0)
+			for _, id := range sortedIDs(a.placementGroups) {
+				placementGroup := a.placementGroups[id]
+				if name := query.Get("name"); name != "" && placementGroup.Name != name {
+					continue
+				}
+				if !matchesSelector(placementGroup.Labels, query.Get("label_selector")) {
+					continue
+				}
+				placementGroups = append(placementGroups, *placementGroup)
+			}
+
+			writeJSON(w, http.StatusOK, schema.PlacementGroupListResponse{PlacementGroups: placementGroups})
+		case http.MethodPost:
+			var req schema.PlacementGroupCreateRequest
+			if !decode(w, r, &req) {
+				return
+			}
+			if req.Type != string(hcloudsdk.PlacementGroupTypeSpread) {
+				invalidInput(w, "unknown placement group type %s", req.Type)
+				return
+			}
+
+			placementGroup := &schema.PlacementGroup{
+				ID:      a.newID(),
+				Name:    req.Name,
+				Labels:  labelsOrEmpty(req.Labels),
+				Created: time.Now(),
+				Servers: []int64{},
+				Type:    req.Type,
+			}
+			a.placementGroups[placementGroup.ID] = placementGroup
+
+			writeJSON(w, http.StatusCreated, schema.PlacementGroupCreateResponse{PlacementGroup: *placementGroup})
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	placementGroup, ok := a.placementGroups[id]
+	if !ok || len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.PlacementGroupGetResponse{PlacementGroup: *placementGroup})
+	case http.MethodPut:
+		var req schema.PlacementGroupUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			placementGroup.Name = *req.Name
+		}
+		if req.Labels != nil {
+			placementGroup.Labels = *req.Labels
+		}
+		writeJSON(w, http.StatusOK, schema.PlacementGroupUpdateResponse{PlacementGroup: *placementGroup})
+	case http.MethodDelete:
+		for _, server := range a.servers {
+			if server.PlacementGroup != nil && server.PlacementGroup.ID == id {
+				server.PlacementGroup = nil
+			}
+		}
+		delete(a.placementGroups, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
@@ -0,0 +1,100 @@
+package fake
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// matchesSelector evaluates a Hetzner label selector against the labels.
+// https://docs.hetzner.cloud/#label-selector
+func matchesSelector(labels map[string]string, selector string) bool {
+	for _, requirement := range splitRequirements(selector) {
+		if !matchesRequirement(labels, requirement) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesRequirement(labels map[string]string, requirement string) bool {
+	if key, values, ok := cutSet(requirement, " notin "); ok {
+		value, exists := labels[key]
+		return !exists || !slices.Contains(values, value)
+	}
+	if key, values, ok := cutSet(requirement, " in "); ok {
+		value, exists := labels[key]
+		return exists && slices.Contains(values, value)
+	}
+	if key, value, ok := strings.Cut(requirement, "!="); ok {
+		return labels[strings.TrimSpace(key)] != strings.TrimSpace(value)
+	}
+	if key, value, ok := strings.Cut(requirement, "=="); ok {
+		v, exists := labels[strings.TrimSpace(key)]
+		return exists && v == strings.TrimSpace(value)
+	}
+	if key, value, ok := strings.Cut(requirement, "="); ok {
+		v, exists := labels[strings.TrimSpace(key)]
+		return exists && v == strings.TrimSpace(value)
+	}
+	if key, ok := strings.CutPrefix(requirement, "!"); ok {
+		_, exists := labels[strings.TrimSpace(key)]
+		return !exists
+	}
+
+	_, exists := labels[strings.TrimSpace(requirement)]
+	return exists
+}
+
+// splitRequirements splits a selector on the commas which are not inside a
+// set of values
+func splitRequirements(selector string) []string {
+	requirements := make([]string, 0)
+
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	requirements = append(requirements, selector[start:])
+
+	return slices.DeleteFunc(requirements, func(s string) bool {
+		return strings.TrimSpace(s) == ""
+	})
+}
+
+func cutSet(requirement, operator string) (string, []string, bool) {
+	key, set, ok := strings.Cut(requirement, operator)
+	if !ok {
+		return "", nil, false
+	}
+
+	values := strings.Split(strings.Trim(strings.TrimSpace(set), "()"), ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return strings.TrimSpace(key), values, true
+}
+
+func sortedIDs[T any](m map[int64]T) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
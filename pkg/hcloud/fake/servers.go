@@ -0,0 +1,413 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+const rootPassword = "fake-root-password"
+
+// AddServer seeds a server, assigning an ID and defaults for any unset
+// fields. The stored server is returned.
+func (a *API) AddServer(server schema.Server) schema.Server {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if server.ID == 0 {
+		server.ID = a.newID()
+	}
+	if server.Status == "" {
+		server.Status = string(hcloudsdk.ServerStatusRunning)
+	}
+	if server.Created.IsZero() {
+		server.Created = time.Now()
+	}
+	if server.ServerType.ID == 0 {
+		server.ServerType = a.serverTypes[0]
+	}
+	if server.Datacenter.ID == 0 {
+		server.Datacenter = a.datacenters[0]
+	}
+	if server.Labels == nil {
+		server.Labels = map[string]string{}
+	}
+
+	a.servers[server.ID] = &server
+	a.syncServerFirewalls()
+
+	return *a.servers[server.ID]
+}
+
+// Server returns the stored server with the given ID
+func (a *API) Server(id int64) (schema.Server, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	server, ok := a.servers[id]
+	if !ok {
+		return schema.Server{}, false
+	}
+
+	return *server, true
+}
+
+func (a *API) handleServers(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			a.listServers(w, r)
+		case http.MethodPost:
+			a.createServer(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	server, ok := a.servers[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(path) == 3 && path[1] == "actions" && r.Method == http.MethodPost {
+		a.serverAction(w, r, server, path[2])
+		return
+	}
+	if len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.ServerGetResponse{Server: *server})
+	case http.MethodPut:
+		var req schema.ServerUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != "" {
+			server.Name = req.Name
+		}
+		if req.Labels != nil {
+			server.Labels = *req.Labels
+			a.syncServerFirewalls()
+		}
+		writeJSON(w, http.StatusOK, schema.ServerUpdateResponse{Server: *server})
+	case http.MethodDelete:
+		a.deleteServer(server)
+		writeJSON(w, http.StatusOK, schema.ServerDeleteResponse{
+			Action: a.newAction("delete_server", "server", server.ID),
+		})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) listServers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	servers := make([]schema.Server, 0)
+	for _, id := range sortedIDs(a.servers) {
+		server := a.servers[id]
+		if name := query.Get("name"); name != "" && server.Name != name {
+			continue
+		}
+		if !matchesSelector(server.Labels, query.Get("label_selector")) {
+			continue
+		}
+
+		servers = append(servers, *server)
+	}
+
+	writeJSON(w, http.StatusOK, schema.ServerListResponse{Servers: servers})
+}
+
+func (a *API) createServer(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	var req schema.ServerCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	for _, server := range a.servers {
+		if server.Name == req.Name {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeUniquenessError, "server name is already used")
+			return
+		}
+	}
+
+	serverType, ok := a.findServerType(req.ServerType)
+	if !ok {
+		invalidInput(w, "unknown server type %v", req.ServerType)
+		return
+	}
+
+	image, ok := a.findImage(req.Image, serverType.Architecture)
+	if !ok {
+		invalidInput(w, "unknown image %v for architecture %s", req.Image, serverType.Architecture)
+		return
+	}
+
+	datacenter := a.datacenters[0]
+	switch {
+	case req.Datacenter != "":
+		if datacenter, ok = a.findDatacenter(req.Datacenter); !ok {
+			invalidInput(w, "unknown datacenter %s", req.Datacenter)
+			return
+		}
+	case req.Location != "":
+		location, ok := a.findLocation(req.Location)
+		if !ok {
+			invalidInput(w, "unknown location %s", req.Location)
+			return
+		}
+		for _, d := range a.datacenters {
+			if d.Location.ID == location.ID {
+				datacenter = d
+				break
+			}
+		}
+	}
+
+	id := a.newID()
+	server := &schema.Server{
+		ID:         id,
+		Name:       req.Name,
+		Status:     string(hcloudsdk.ServerStatusRunning),
+		Created:    time.Now(),
+		ServerType: serverType,
+		Datacenter: datacenter,
+		Image:      &image,
+		Labels:     labelsOrEmpty(req.Labels),
+		PublicNet: schema.ServerPublicNet{
+			Firewalls: []schema.ServerFirewall{},
+		},
+		PrivateNet:      []schema.ServerPrivateNet{},
+		Volumes:         []int64{},
+		PrimaryDiskSize: serverType.Disk,
+	}
+
+	if req.StartAfterCreate != nil && !*req.StartAfterCreate {
+		server.Status = string(hcloudsdk.ServerStatusOff)
+	}
+
+	if req.PublicNet == nil || req.PublicNet.EnableIPv4 {
+		server.PublicNet.IPv4 = schema.ServerPublicNetIPv4{
+			ID: a.newID(),
+			IP: fmt.Sprintf("203.0.113.%d", id%254+1),
+		}
+	}
+	if req.PublicNet == nil || req.PublicNet.EnableIPv6 {
+		server.PublicNet.IPv6 = schema.ServerPublicNetIPv6{
+			ID: a.newID(),
+			IP: fmt.Sprintf("2001:db8:%x::/64", id),
+		}
+	}
+
+	// Check every reference before changing any state
+	for _, f := range req.Firewalls {
+		if _, ok := a.firewalls[f.Firewall]; !ok {
+			invalidInput(w, "unknown firewall %d", f.Firewall)
+			return
+		}
+	}
+	for _, networkID := range req.Networks {
+		if _, ok := a.networks[networkID]; !ok {
+			invalidInput(w, "unknown network %d", networkID)
+			return
+		}
+	}
+	for _, volumeID := range req.Volumes {
+		volume, ok := a.volumes[volumeID]
+		if !ok {
+			invalidInput(w, "unknown volume %d", volumeID)
+			return
+		}
+		if volume.Server != nil {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeVolumeAlreadyAttached, "volume is already attached")
+			return
+		}
+	}
+	if _, ok := a.placementGroups[req.PlacementGroup]; req.PlacementGroup != 0 && !ok {
+		invalidInput(w, "unknown placement group %d", req.PlacementGroup)
+		return
+	}
+	for _, keyID := range req.SSHKeys {
+		if _, ok := a.sshKeys[keyID]; !ok {
+			invalidInput(w, "unknown ssh key %d", keyID)
+			return
+		}
+	}
+
+	for _, f := range req.Firewalls {
+		firewall := a.firewalls[f.Firewall]
+		firewall.AppliedTo = append(firewall.AppliedTo, ServerResource(id))
+	}
+	for _, networkID := range req.Networks {
+		server.PrivateNet = append(server.PrivateNet, a.attachToNetwork(a.networks[networkID], id, nil))
+	}
+	for _, volumeID := range req.Volumes {
+		a.volumes[volumeID].Server = &id
+		server.Volumes = append(server.Volumes, volumeID)
+	}
+	if placementGroup, ok := a.placementGroups[req.PlacementGroup]; ok {
+		placementGroup.Servers = append(placementGroup.Servers, id)
+		server.PlacementGroup = placementGroup
+	}
+
+	a.servers[id] = server
+	a.syncServerFirewalls()
+
+	var password *string
+	if len(req.SSHKeys) == 0 {
+		p := rootPassword
+		password = &p
+	}
+
+	writeJSON(w, http.StatusCreated, schema.ServerCreateResponse{
+		Server:       *server,
+		Action:       a.newAction("create_server", "server", id),
+		RootPassword: password,
+		NextActions:  []schema.Action{a.newAction("start_server", "server", id)},
+	})
+}
+
+func (a *API) deleteServer(server *schema.Server) {
+	delete(a.servers, server.ID)
+
+	for _, firewall := range a.firewalls {
+		firewall.AppliedTo = slices.DeleteFunc(firewall.AppliedTo, func(r schema.FirewallResource) bool {
+			return r.Server != nil && r.Server.ID == server.ID
+		})
+	}
+
+	for _, network := range a.networks {
+		network.Servers = slices.DeleteFunc(network.Servers, func(id int64) bool {
+			return id == server.ID
+		})
+	}
+
+	for _, placementGroup := range a.placementGroups {
+		placementGroup.Servers = slices.DeleteFunc(placementGroup.Servers, func(id int64) bool {
+			return id == server.ID
+		})
+	}
+
+	for _, volume := range a.volumes {
+		if volume.Server != nil && *volume.Server == server.ID {
+			volume.Server = nil
+		}
+	}
+}
+
+func (a *API) serverAction(w http.ResponseWriter, r *http.Request, server *schema.Server, command string) { //nolint:gocyclo
+	switch command {
+	case "poweron", "reboot", "reset":
+		server.Status = string(hcloudsdk.ServerStatusRunning)
+	case "poweroff", "shutdown":
+		server.Status = string(hcloudsdk.ServerStatusOff)
+	case "reset_password":
+		writeJSON(w, http.StatusCreated, schema.ServerActionResetPasswordResponse{
+			Action:       a.newAction(command, "server", server.ID),
+			RootPassword: rootPassword,
+		})
+		return
+	case "attach_iso":
+		var req schema.ServerActionAttachISORequest
+		if !decode(w, r, &req) {
+			return
+		}
+		iso, ok := a.findISO(req.ISO)
+		if !ok {
+			invalidInput(w, "unknown iso %v", req.ISO)
+			return
+		}
+		if iso.Architecture != nil && *iso.Architecture != server.ServerType.Architecture {
+			invalidInput(w, "iso architecture %s does not match server architecture %s", *iso.Architecture, server.ServerType.Architecture)
+			return
+		}
+		server.ISO = &iso
+	case "detach_iso":
+		server.ISO = nil
+	case "attach_to_network":
+		var req schema.ServerActionAttachToNetworkRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		network, ok := a.networks[req.Network]
+		if !ok {
+			invalidInput(w, "unknown network %d", req.Network)
+			return
+		}
+		if slices.Contains(network.Servers, server.ID) {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeServerAlreadyAttached, "server is already attached to the network")
+			return
+		}
+		server.PrivateNet = append(server.PrivateNet, a.attachToNetwork(network, server.ID, req.IP))
+	case "detach_from_network":
+		var req schema.ServerActionDetachFromNetworkRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		network, ok := a.networks[req.Network]
+		if !ok || !slices.Contains(network.Servers, server.ID) {
+			invalidInput(w, "server is not attached to network %d", req.Network)
+			return
+		}
+		network.Servers = slices.DeleteFunc(network.Servers, func(id int64) bool {
+			return id == server.ID
+		})
+		server.PrivateNet = slices.DeleteFunc(server.PrivateNet, func(n schema.ServerPrivateNet) bool {
+			return n.Network == req.Network
+		})
+	case "add_to_placement_group":
+		var req schema.ServerActionAddToPlacementGroupRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		placementGroup, ok := a.placementGroups[req.PlacementGroup]
+		if !ok {
+			invalidInput(w, "unknown placement group %d", req.PlacementGroup)
+			return
+		}
+		if server.Status != string(hcloudsdk.ServerStatusOff) {
+			writeError(w, http.StatusUnprocessableEntity, hcloudsdk.ErrorCodeServerNotStopped, "server must be stopped")
+			return
+		}
+		if server.PlacementGroup != nil {
+			writeError(w, http.StatusUnprocessableEntity, hcloudsdk.ErrorCodeServerAlreadyAdded, "server is already in a placement group")
+			return
+		}
+		placementGroup.Servers = append(placementGroup.Servers, server.ID)
+		server.PlacementGroup = placementGroup
+	case "remove_from_placement_group":
+		if server.PlacementGroup == nil {
+			invalidInput(w, "server is not in a placement group")
+			return
+		}
+		if placementGroup, ok := a.placementGroups[server.PlacementGroup.ID]; ok {
+			placementGroup.Servers = slices.DeleteFunc(placementGroup.Servers, func(id int64) bool {
+				return id == server.ID
+			})
+		}
+		server.PlacementGroup = nil
+	default:
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, schema.ServerActionPoweronResponse{
+		Action: a.newAction(command, "server", server.ID),
+	})
+}
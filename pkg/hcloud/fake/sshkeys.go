@@ -0,0 +1,132 @@
+package fake
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// SSHKeys returns every stored SSH key
+func (a *API) SSHKeys() []schema.SSHKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	keys := make([]schema.SSHKey, 0, len(a.sshKeys))
+	for _, id := range sortedIDs(a.sshKeys) {
+		keys = append(keys, *a.sshKeys[id])
+	}
+
+	return keys
+}
+
+func (a *API) handleSSHKeys(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+
+			keys := make([]schema.SSHKey, 0)
+			for _, id := range sortedIDs(a.sshKeys) {
+				key := a.sshKeys[id]
+				if name := query.Get("name"); name != "" && key.Name != name {
+					continue
+				}
+				if fingerprint := query.Get("fingerprint"); fingerprint != "" && key.Fingerprint != fingerprint {
+					continue
+				}
+				if !matchesSelector(key.Labels, query.Get("label_selector")) {
+					continue
+				}
+				keys = append(keys, *key)
+			}
+
+			writeJSON(w, http.StatusOK, schema.SSHKeyListResponse{SSHKeys: keys})
+		case http.MethodPost:
+			a.createSSHKey(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	key, ok := a.sshKeys[id]
+	if !ok || len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.SSHKeyGetResponse{SSHKey: *key})
+	case http.MethodDelete:
+		delete(a.sshKeys, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) createSSHKey(w http.ResponseWriter, r *http.Request) {
+	var req schema.SSHKeyCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	fingerprint, err := fingerprintSSHKey(req.PublicKey)
+	if err != nil {
+		invalidInput(w, "invalid public key: %s", err)
+		return
+	}
+
+	for _, key := range a.sshKeys {
+		if key.Name == req.Name || key.Fingerprint == fingerprint {
+			writeError(w, http.StatusConflict, hcloudsdk.ErrorCodeUniquenessError, "SSH key with the same name or fingerprint already exists")
+			return
+		}
+	}
+
+	key := &schema.SSHKey{
+		ID:          a.newID(),
+		Name:        req.Name,
+		Fingerprint: fingerprint,
+		PublicKey:   req.PublicKey,
+		Labels:      labelsOrEmpty(req.Labels),
+		Created:     time.Now(),
+	}
+	a.sshKeys[key.ID] = key
+
+	writeJSON(w, http.StatusCreated, schema.SSHKeyCreateResponse{SSHKey: *key})
+}
+
+// fingerprintSSHKey returns the MD5 fingerprint Hetzner gives a public key
+func fingerprintSSHKey(publicKey string) (string, error) {
+	parts := strings.Fields(publicKey)
+	if len(parts) < 2 {
+		return "", fmt.Errorf("bad ssh key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	sum := md5.Sum(data) //nolint:gosec
+
+	octets := make([]string, 0, len(sum))
+	for _, b := range sum {
+		octets = append(octets, fmt.Sprintf("%02x", b))
+	}
+
+	return strings.Join(octets, ":"), nil
+}
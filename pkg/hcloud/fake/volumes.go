@@ -0,0 +1,239 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	hcloudsdk "github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// AddVolume seeds a volume, assigning an ID and defaults for any unset
+// fields. The stored volume is returned.
+func (a *API) AddVolume(volume schema.Volume) schema.Volume {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if volume.ID == 0 {
+		volume.ID = a.newID()
+	}
+	if volume.Status == "" {
+		volume.Status = string(hcloudsdk.VolumeStatusAvailable)
+	}
+	if volume.Created.IsZero() {
+		volume.Created = time.Now()
+	}
+	if volume.Location.ID == 0 {
+		volume.Location = a.locations[0]
+	}
+	if volume.Labels == nil {
+		volume.Labels = map[string]string{}
+	}
+	if volume.LinuxDevice == "" {
+		volume.LinuxDevice = linuxDevice(volume.ID)
+	}
+
+	a.volumes[volume.ID] = &volume
+
+	return volume
+}
+
+// Volume returns the stored volume with the given ID
+func (a *API) Volume(id int64) (schema.Volume, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	volume, ok := a.volumes[id]
+	if !ok {
+		return schema.Volume{}, false
+	}
+
+	return *volume, true
+}
+
+func (a *API) handleVolumes(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+
+			volumes := make([]schema.Volume, 0)
+			for _, id := range sortedIDs(a.volumes) {
+				volume := a.volumes[id]
+				if name := query.Get("name"); name != "" && volume.Name != name {
+					continue
+				}
+				if !matchesSelector(volume.Labels, query.Get("label_selector")) {
+					continue
+				}
+				volumes = append(volumes, *volume)
+			}
+
+			writeJSON(w, http.StatusOK, schema.VolumeListResponse{Volumes: volumes})
+		case http.MethodPost:
+			a.createVolume(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	id, ok := parseID(w, path[0])
+	if !ok {
+		return
+	}
+
+	volume, ok := a.volumes[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	if len(path) == 3 && path[1] == "actions" && r.Method == http.MethodPost {
+		a.volumeAction(w, r, volume, path[2])
+		return
+	}
+	if len(path) > 1 {
+		notFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schema.VolumeGetResponse{Volume: *volume})
+	case http.MethodPut:
+		var req schema.VolumeUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != "" {
+			volume.Name = req.Name
+		}
+		if req.Labels != nil {
+			volume.Labels = *req.Labels
+		}
+		writeJSON(w, http.StatusOK, schema.VolumeUpdateResponse{Volume: *volume})
+	case http.MethodDelete:
+		if volume.Server != nil {
+			writeError(w, http.StatusLocked, hcloudsdk.ErrorCodeLocked, "volume is attached to a server")
+			return
+		}
+		delete(a.volumes, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (a *API) createVolume(w http.ResponseWriter, r *http.Request) {
+	var req schema.VolumeCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var server *schema.Server
+	var location schema.Location
+
+	switch {
+	case req.Server != nil:
+		s, ok := a.servers[*req.Server]
+		if !ok {
+			invalidInput(w, "unknown server %d", *req.Server)
+			return
+		}
+		server = s
+		location = s.Datacenter.Location
+	case req.Location != nil:
+		l, ok := a.findLocation(req.Location)
+		if !ok {
+			invalidInput(w, "unknown location %v", req.Location)
+			return
+		}
+		location = l
+	default:
+		invalidInput(w, "one of server or location is required")
+		return
+	}
+
+	volume := &schema.Volume{
+		ID:       a.newID(),
+		Name:     req.Name,
+		Status:   string(hcloudsdk.VolumeStatusAvailable),
+		Location: location,
+		Size:     req.Size,
+		Format:   req.Format,
+		Labels:   labelsOrEmpty(req.Labels),
+		Created:  time.Now(),
+	}
+	volume.LinuxDevice = linuxDevice(volume.ID)
+
+	nextActions := make([]schema.Action, 0)
+	if server != nil {
+		volume.Server = &server.ID
+		server.Volumes = append(server.Volumes, volume.ID)
+		nextActions = append(nextActions, a.newAction("attach_volume", "volume", volume.ID))
+	}
+
+	a.volumes[volume.ID] = volume
+
+	action := a.newAction("create_volume", "volume", volume.ID)
+
+	writeJSON(w, http.StatusCreated, schema.VolumeCreateResponse{
+		Volume:      *volume,
+		Action:      &action,
+		NextActions: nextActions,
+	})
+}
+
+func (a *API) volumeAction(w http.ResponseWriter, r *http.Request, volume *schema.Volume, command string) {
+	switch command {
+	case "attach":
+		var req schema.VolumeActionAttachVolumeRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		server, ok := a.servers[req.Server]
+		if !ok {
+			invalidInput(w, "unknown server %d", req.Server)
+			return
+		}
+		if volume.Server != nil {
+			writeError(w, http.StatusUnprocessableEntity, hcloudsdk.ErrorCodeVolumeAlreadyAttached, "volume is already attached")
+			return
+		}
+		volume.Server = &server.ID
+		server.Volumes = append(server.Volumes, volume.ID)
+	case "detach":
+		if volume.Server != nil {
+			if server, ok := a.servers[*volume.Server]; ok {
+				server.Volumes = slices.DeleteFunc(server.Volumes, func(id int64) bool {
+					return id == volume.ID
+				})
+			}
+		}
+		volume.Server = nil
+	case "resize":
+		var req schema.VolumeActionResizeVolumeRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Size < volume.Size {
+			invalidInput(w, "volumes can only be made larger")
+			return
+		}
+		volume.Size = req.Size
+	default:
+		notFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, schema.VolumeActionAttachVolumeResponse{
+		Action: a.newAction(command+"_volume", "volume", volume.ID),
+	})
+}
+
+func linuxDevice(id int64) string {
+	return fmt.Sprintf("/dev/disk/by-id/scsi-0HC_Volume_%d", id)
+}
@@ -0,0 +1,11 @@
+//go:build generate
+// +build generate
+
+// Generate mocks of the service interfaces in api.go
+//go:generate go run -tags generate go.uber.org/mock/mockgen -source=api.go -destination=fake/mock.go -package=fake -write_package_comment=false
+
+package hcloud
+
+import (
+	_ "go.uber.org/mock/mockgen" //nolint:typecheck
+)
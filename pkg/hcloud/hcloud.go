@@ -5,17 +5,116 @@ import (
 	"crypto/md5" //nolint:gosec
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/pkg/errors"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
-// Client is used to interact with the Hetzner API
+// Client is used to interact with the Hetzner API. Each service is an
+// interface so it can be replaced in tests.
 type Client struct {
-	Client *hcloud.Client
+	Action         ActionAPI
+	Datacenter     DatacenterAPI
+	Firewall       FirewallAPI
+	Image          ImageAPI
+	ISO            ISOAPI
+	Location       LocationAPI
+	Network        NetworkAPI
+	PlacementGroup PlacementGroupAPI
+	Server         ServerAPI
+	ServerType     ServerTypeAPI
+	SSHKey         SSHKeyAPI
+	Volume         VolumeAPI
+
+	defaultLabels  map[string]string
+	labelMode      apisv1alpha1.LabelMode
+	pollInterval   time.Duration
+	clientOpts     []hcloud.ClientOption
+	providerConfig string
+	project        string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithDefaultLabels adds labels to every resource created by the Client.
+// Labels given to ApplyDefaultLabels take precedence.
+func WithDefaultLabels(labels map[string]string) Option {
+	return func(c *Client) {
+		c.defaultLabels = labels
+	}
+}
+
+// WithLabelMode sets how the Client handles labels which do not fit the
+// Hetzner label grammar. Invalid labels are rejected by default.
+func WithLabelMode(mode apisv1alpha1.LabelMode) Option {
+	return func(c *Client) {
+		c.labelMode = mode
+	}
+}
+
+// WithPollInterval sets how often WaitForActionCompletion checks the status
+// of an action. Defaults to one second.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+// WithProviderConfig names the ProviderConfig and, if set, the project the
+// Client's credentials came from, so metrics can be told apart
+func WithProviderConfig(name string, project *string) Option {
+	return func(c *Client) {
+		c.providerConfig = name
+		if project != nil {
+			c.project = *project
+		}
+	}
+}
+
+// WithClientOptions passes options through to the underlying hcloud-go
+// client, such as a different endpoint or HTTP client
+func WithClientOptions(opts ...hcloud.ClientOption) Option {
+	return func(c *Client) {
+		c.clientOpts = append(c.clientOpts, opts...)
+	}
+}
+
+// ApplyDefaultLabels merges the provider labels, the Client's default labels
+// and the given labels, in that order of precedence. The result is validated
+// or sanitised according to the Client's label mode.
+func (c *Client) ApplyDefaultLabels(input ...map[string]string) (map[string]string, error) {
+	return ApplyLabelMode(c.labelMode, ApplyDefaultLabels(append([]map[string]string{c.defaultLabels}, input...)...))
+}
+
+// UpdateLabels builds the labels for an existing resource. The generated-at
+// label is carried over from the live labels so it records the creation time
+// rather than the time of the last update.
+func (c *Client) UpdateLabels(live map[string]string, input ...map[string]string) (map[string]string, error) {
+	labels, err := c.ApplyDefaultLabels(input...)
+	if err != nil {
+		return nil, err
+	}
+
+	return PreserveGeneratedDateTime(labels, live), nil
+}
+
+// LabelsUpToDate reports whether the live labels match the labels the
+// resource would be given, ignoring the generated-at label
+func (c *Client) LabelsUpToDate(live map[string]string, input ...map[string]string) bool {
+	labels, err := c.ApplyDefaultLabels(input...)
+	if err != nil {
+		// Let the update report why the labels are invalid
+		return false
+	}
+
+	return LabelsEqual(labels, live)
 }
 
 func (c *Client) UpsertSSHKeys(ctx context.Context, publicKeys ...string) ([]*hcloud.SSHKey, error) {
@@ -38,7 +137,7 @@ func (c *Client) UpsertSSHKey(ctx context.Context, publicKey string) (*hcloud.SS
 		return nil, errors.Wrap(err, "failed to generate fingerprint for public ssh key")
 	}
 
-	sshKey, _, err := c.Client.SSHKey.GetByFingerprint(ctx, fingerprint)
+	sshKey, _, err := c.SSHKey.GetByFingerprint(ctx, fingerprint)
 	if err != nil {
 		return nil, err
 	}
@@ -47,11 +146,16 @@ func (c *Client) UpsertSSHKey(ctx context.Context, publicKey string) (*hcloud.SS
 		return sshKey, nil
 	}
 
+	labels, err := c.ApplyDefaultLabels()
+	if err != nil {
+		return nil, err
+	}
+
 	// Upload the key
-	uploadedSSHKey, _, err := c.Client.SSHKey.Create(ctx, hcloud.SSHKeyCreateOpts{
+	uploadedSSHKey, _, err := c.SSHKey.Create(ctx, hcloud.SSHKeyCreateOpts{
 		Name:      uuid.NewString(),
 		PublicKey: publicKey,
-		Labels:    ApplyDefaultLabels(),
+		Labels:    labels,
 	})
 	if err != nil {
 		return nil, err
@@ -62,7 +166,7 @@ func (c *Client) UpsertSSHKey(ctx context.Context, publicKey string) (*hcloud.SS
 
 func (c *Client) GetDatacenterOrLocation(ctx context.Context, datacenter, location *string) (*hcloud.Datacenter, *hcloud.Location, error) {
 	if datacenter != nil {
-		datacenterType, _, err := c.Client.Datacenter.GetByName(ctx, *datacenter)
+		datacenterType, _, err := c.Datacenter.GetByName(ctx, *datacenter)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "failed to get datacenter")
 		}
@@ -73,7 +177,7 @@ func (c *Client) GetDatacenterOrLocation(ctx context.Context, datacenter, locati
 
 	}
 	if location != nil {
-		locationType, _, err := c.Client.Location.GetByName(ctx, *location)
+		locationType, _, err := c.Location.GetByName(ctx, *location)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "failed to get location")
 		}
@@ -105,25 +209,37 @@ func (c *Client) WaitForActionCompletion(ctx context.Context, action *hcloud.Act
 	startTime := time.Now()
 	timeoutTime := startTime.Add(timeout[0])
 
+	// Every wait is observed, however it ends
+	result := actionResultRequestFailed
+	defer func() {
+		observeActionWait(action.Command, result, startTime)
+	}()
+
 	for {
-		time.Sleep(time.Second)
+		time.Sleep(c.pollInterval)
 
 		now := time.Now()
 
 		if now.After(timeoutTime) {
+			result = actionResultTimeout
 			return fmt.Errorf("action timed out")
 		}
 
-		status, _, err := c.Client.Action.GetByID(ctx, action.ID)
+		status, _, err := c.Action.GetByID(ctx, action.ID)
 		if err != nil {
 			return err
 		}
+		if status == nil {
+			return fmt.Errorf("action %d not found", action.ID)
+		}
 
 		if status.Status == hcloud.ActionStatusError {
+			result = actionResultError
 			return fmt.Errorf("%s: %s", status.ErrorCode, status.ErrorMessage)
 		}
 
 		if status.Status == hcloud.ActionStatusSuccess {
+			result = actionResultSuccess
 			break
 		}
 	}
@@ -131,10 +247,40 @@ func (c *Client) WaitForActionCompletion(ctx context.Context, action *hcloud.Act
 	return nil
 }
 
-func NewClient(token string) (*Client, error) {
-	return &Client{
-		Client: hcloud.NewClient(hcloud.WithToken(token)),
-	}, nil
+func NewClient(token string, opts ...Option) (*Client, error) {
+	c := &Client{
+		pollInterval: time.Second,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	client := hcloud.NewClient(append([]hcloud.ClientOption{
+		hcloud.WithToken(token),
+		hcloud.WithHTTPClient(&http.Client{
+			Transport: &instrumentedTransport{
+				next:           http.DefaultTransport,
+				providerConfig: c.providerConfig,
+				project:        c.project,
+			},
+		}),
+	}, c.clientOpts...)...)
+
+	c.Action = &client.Action
+	c.Datacenter = &client.Datacenter
+	c.Firewall = &client.Firewall
+	c.Image = &client.Image
+	c.ISO = &client.ISO
+	c.Location = &client.Location
+	c.Network = &client.Network
+	c.PlacementGroup = &client.PlacementGroup
+	c.Server = &client.Server
+	c.ServerType = &client.ServerType
+	c.SSHKey = &client.SSHKey
+	c.Volume = &client.Volume
+
+	return c, nil
 }
 
 func generateSSHKeyFingerprint(publicKey string) (fingerprint string, err error) {
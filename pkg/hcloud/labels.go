@@ -2,15 +2,37 @@ package hcloud
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
 )
 
 const (
 	GeneratedDateTime = "crossplane.io/generated-at"
 	ProviderLabel     = "crossplane.io/provider"
 	Provider          = "provider-hetzner"
+
+	// Limits from https://docs.hetzner.cloud/#labels
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+	maxLabelValueLength  = 63
+	reservedLabelPrefix  = "hetzner.cloud"
+)
+
+var (
+	labelNameRegexp   = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?$`)
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	labelValueRegexp  = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?)?$`)
+
+	invalidNameCharsRegexp   = regexp.MustCompile(`[^-a-zA-Z0-9_.]`)
+	invalidPrefixCharsRegexp = regexp.MustCompile(`[^-a-z0-9.]`)
 )
 
 func ApplyDefaultLabels(input ...map[string]string) map[string]string {
@@ -19,7 +41,6 @@ func ApplyDefaultLabels(input ...map[string]string) map[string]string {
 		GeneratedDateTime: strconv.FormatInt(time.Now().Unix(), 10),
 	}
 
-	// @todo(sje): consider doing some sanitising of keys/values https://docs.hetzner.cloud/#labels
 	for _, i := range input {
 		for k, v := range i {
 			labels[k] = v
@@ -29,22 +50,280 @@ func ApplyDefaultLabels(input ...map[string]string) map[string]string {
 	return labels
 }
 
+// PreserveGeneratedDateTime copies the generated-at label from the live labels,
+// if set, so the creation timestamp is stable across updates
+func PreserveGeneratedDateTime(labels, live map[string]string) map[string]string {
+	if generatedAt, ok := live[GeneratedDateTime]; ok {
+		labels[GeneratedDateTime] = generatedAt
+	}
+
+	return labels
+}
+
+// LabelsEqual compares two sets of labels, ignoring the generated-at label as
+// it is only set when a resource is created
+func LabelsEqual(a, b map[string]string) bool {
+	if len(withoutGeneratedDateTime(a)) != len(withoutGeneratedDateTime(b)) {
+		return false
+	}
+
+	for k, v := range withoutGeneratedDateTime(a) {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateLabelKey checks a key against the Hetzner label grammar. A key is
+// an optional DNS subdomain prefix and a slash, followed by a name of up to
+// 63 alphanumeric characters, dashes, underscores and dots which must start
+// and end with an alphanumeric character.
+func ValidateLabelKey(key string) error {
+	prefix, name, hasPrefix := strings.Cut(key, "/")
+	if !hasPrefix {
+		name = prefix
+		prefix = ""
+	}
+
+	if hasPrefix {
+		if len(prefix) > maxLabelPrefixLength {
+			return fmt.Errorf("prefix must be no more than %d characters", maxLabelPrefixLength)
+		}
+		if !labelPrefixRegexp.MatchString(prefix) {
+			return fmt.Errorf("prefix must be a lowercase DNS subdomain")
+		}
+		if prefix == reservedLabelPrefix {
+			return fmt.Errorf("prefix %s is reserved by Hetzner", reservedLabelPrefix)
+		}
+	}
+
+	if len(name) > maxLabelNameLength {
+		return fmt.Errorf("name must be no more than %d characters", maxLabelNameLength)
+	}
+	if !labelNameRegexp.MatchString(name) {
+		return fmt.Errorf("name must consist of alphanumeric characters, '-', '_' or '.' and start and end with an alphanumeric character")
+	}
+
+	return nil
+}
+
+// ValidateLabelValue checks a value against the Hetzner label grammar. A value
+// is either empty or up to 63 alphanumeric characters, dashes, underscores
+// and dots which must start and end with an alphanumeric character.
+func ValidateLabelValue(value string) error {
+	if len(value) > maxLabelValueLength {
+		return fmt.Errorf("must be no more than %d characters", maxLabelValueLength)
+	}
+	if !labelValueRegexp.MatchString(value) {
+		return fmt.Errorf("must be empty or consist of alphanumeric characters, '-', '_' or '.' and start and end with an alphanumeric character")
+	}
+
+	return nil
+}
+
+// ValidateLabels checks every key and value, reporting all invalid labels
+func ValidateLabels(labels map[string]string) error {
+	problems := make([]string, 0)
+
+	for _, k := range sortedKeys(labels) {
+		if err := ValidateLabelKey(k); err != nil {
+			problems = append(problems, fmt.Sprintf("key %q: %s", k, err))
+		}
+		if err := ValidateLabelValue(labels[k]); err != nil {
+			problems = append(problems, fmt.Sprintf("value %q of key %q: %s", labels[k], k, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid labels: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// SanitiseLabels rewrites labels to fit the Hetzner label grammar. Invalid
+// characters are replaced with dashes and over-long parts are truncated.
+// Labels whose key cannot be rewritten are dropped. An error is returned if
+// two keys are rewritten to the same key, rather than letting one overwrite
+// the other.
+func SanitiseLabels(labels map[string]string) (map[string]string, error) {
+	sanitised := make(map[string]string, len(labels))
+	sources := make(map[string]string, len(labels))
+
+	for _, k := range sortedKeys(labels) {
+		key, ok := sanitiseLabelKey(k)
+		if !ok {
+			continue
+		}
+
+		if source, ok := sources[key]; ok {
+			return nil, fmt.Errorf("label keys %q and %q both sanitise to %q", source, k, key)
+		}
+
+		sources[key] = k
+		sanitised[key] = sanitiseLabelName(labels[k], maxLabelValueLength)
+	}
+
+	return sanitised, nil
+}
+
+// ApplyLabelMode validates or sanitises the labels according to the mode
+func ApplyLabelMode(mode apisv1alpha1.LabelMode, labels map[string]string) (map[string]string, error) {
+	if mode == apisv1alpha1.LabelModeSanitise {
+		return SanitiseLabels(labels)
+	}
+
+	if err := ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
 func ToSelector(l map[string]string) string {
 	labels := make([]string, 0)
 
-	for k, v := range escapeLabels(l) {
-		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+	for _, k := range sortedKeys(l) {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, l[k]))
 	}
 
 	return strings.Join(labels, ",")
 }
 
-func escapeLabels(labels map[string]string) map[string]string {
-	escaped := make(map[string]string, len(labels))
+// ToLabelSelector converts a Kubernetes label selector to the Hetzner label
+// selector syntax. Requirements are sorted so the same selector always gives
+// the same string. https://docs.hetzner.cloud/#label-selector
+func ToLabelSelector(selector *metav1.LabelSelector) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+
+	requirements := make([]string, 0)
+
+	for _, k := range sortedKeys(selector.MatchLabels) {
+		requirements = append(requirements, fmt.Sprintf("%s==%s", k, selector.MatchLabels[k]))
+	}
+
+	for _, expr := range selector.MatchExpressions {
+		values := append([]string{}, expr.Values...)
+		sort.Strings(values)
+
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn:
+			if len(values) == 0 {
+				return "", fmt.Errorf("label selector operator %s on key %q requires values", expr.Operator, expr.Key)
+			}
+
+			operator := "in"
+			if expr.Operator == metav1.LabelSelectorOpNotIn {
+				operator = "notin"
+			}
+
+			requirements = append(requirements, fmt.Sprintf("%s %s (%s)", expr.Key, operator, strings.Join(values, ",")))
+		case metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist:
+			if len(values) > 0 {
+				return "", fmt.Errorf("label selector operator %s on key %q must not have values", expr.Operator, expr.Key)
+			}
+
+			if expr.Operator == metav1.LabelSelectorOpExists {
+				requirements = append(requirements, expr.Key)
+			} else {
+				requirements = append(requirements, "!"+expr.Key)
+			}
+		default:
+			return "", fmt.Errorf("unknown label selector operator %q", expr.Operator)
+		}
+	}
+
+	if len(requirements) == 0 {
+		return "", fmt.Errorf("label selector must have at least one requirement")
+	}
+
+	// Duplicate requirements are harmless but would make the string unstable
+	sort.Strings(requirements)
+
+	return strings.Join(slices.Compact(requirements), ","), nil
+}
+
+func sanitiseLabelKey(key string) (string, bool) {
+	prefix, name, hasPrefix := strings.Cut(key, "/")
+	if !hasPrefix {
+		name = prefix
+		prefix = ""
+	}
+
+	name = sanitiseLabelName(name, maxLabelNameLength)
+	if name == "" {
+		return "", false
+	}
+
+	if !hasPrefix {
+		return name, true
+	}
+
+	prefix = sanitiseLabelPrefix(prefix)
+	if prefix == reservedLabelPrefix {
+		return "", false
+	}
+	if prefix == "" {
+		return name, true
+	}
+
+	return prefix + "/" + name, true
+}
+
+func sanitiseLabelName(name string, maxLength int) string {
+	name = invalidNameCharsRegexp.ReplaceAllString(name, "-")
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return trimNonAlphanumeric(name)
+}
+
+func sanitiseLabelPrefix(prefix string) string {
+	prefix = invalidPrefixCharsRegexp.ReplaceAllString(strings.ToLower(prefix), "-")
+
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(prefix, ".") {
+		if segment = trimNonAlphanumeric(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	prefix = strings.Join(segments, ".")
+	if len(prefix) > maxLabelPrefixLength {
+		prefix = prefix[:maxLabelPrefixLength]
+	}
+
+	return trimNonAlphanumeric(prefix)
+}
+
+func trimNonAlphanumeric(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
 
+func withoutGeneratedDateTime(labels map[string]string) map[string]string {
+	filtered := make(map[string]string, len(labels))
 	for k, v := range labels {
-		escaped[k] = v
+		if k != GeneratedDateTime {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
-	return escaped
+	return keys
 }
@@ -0,0 +1,298 @@
+package hcloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/mrsimonemms/provider-hetzner/apis/v1alpha1"
+)
+
+func TestValidateLabels(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		labels map[string]string
+		valid  bool
+	}{
+		"Valid": {
+			reason: "Keys with and without a prefix and empty values are valid",
+			labels: map[string]string{
+				"crossplane.io/provider": "provider-hetzner",
+				"team":                   "platform",
+				"empty":                  "",
+				"with_under.score-dash":  "a_b.c-d",
+			},
+			valid: true,
+		},
+		"InvalidKeyCharacter": {
+			reason: "Spaces are not allowed in keys",
+			labels: map[string]string{"cost centre": "123"},
+		},
+		"InvalidValueEnd": {
+			reason: "Values must end with an alphanumeric character",
+			labels: map[string]string{"team": "platform-"},
+		},
+		"UppercasePrefix": {
+			reason: "Prefixes must be lowercase",
+			labels: map[string]string{"Example.com/team": "platform"},
+		},
+		"ReservedPrefix": {
+			reason: "The hetzner.cloud prefix is reserved",
+			labels: map[string]string{"hetzner.cloud/team": "platform"},
+		},
+		"ValueTooLong": {
+			reason: "Values are limited to 63 characters",
+			labels: map[string]string{"team": strings.Repeat("a", 64)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateLabels(tc.labels)
+			if tc.valid != (err == nil) {
+				t.Errorf("\n%s\nValidateLabels(...): want valid %t, got error %v\n", tc.reason, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestSanitiseLabels(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		labels map[string]string
+		want   map[string]string
+		err    bool
+	}{
+		"Unchanged": {
+			reason: "Valid labels are not rewritten",
+			labels: map[string]string{"crossplane.io/provider": "provider-hetzner", "empty": ""},
+			want:   map[string]string{"crossplane.io/provider": "provider-hetzner", "empty": ""},
+		},
+		"Rewritten": {
+			reason: "Invalid characters are replaced and the ends trimmed",
+			labels: map[string]string{"Example.COM/cost centre": "-£100 per month-"},
+			want:   map[string]string{"example.com/cost-centre": "100-per-month"},
+		},
+		"Truncated": {
+			reason: "Values are truncated to 63 characters",
+			labels: map[string]string{"name": strings.Repeat("a", 70)},
+			want:   map[string]string{"name": strings.Repeat("a", 63)},
+		},
+		"Dropped": {
+			reason: "Labels with reserved or empty keys are dropped",
+			labels: map[string]string{"hetzner.cloud/team": "platform", "---": "value"},
+			want:   map[string]string{},
+		},
+		"Collision": {
+			reason: "Keys which sanitise to the same key are an error rather than overwriting each other",
+			labels: map[string]string{"cost centre": "a", "cost-centre": "b"},
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := SanitiseLabels(tc.labels)
+			if tc.err != (err != nil) {
+				t.Errorf("\n%s\nSanitiseLabels(...): want error %t, got %v\n", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSanitiseLabels(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if err := ValidateLabels(got); err != nil {
+				t.Errorf("\n%s\nSanitiseLabels(...): result is invalid: %v\n", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestLabelsEqual(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      map[string]string
+		b      map[string]string
+		want   bool
+	}{
+		"GeneratedDateTimeIgnored": {
+			reason: "Labels which only differ by generated-at are equal",
+			a:      map[string]string{"team": "platform", GeneratedDateTime: "1"},
+			b:      map[string]string{"team": "platform", GeneratedDateTime: "2"},
+			want:   true,
+		},
+		"GeneratedDateTimeMissing": {
+			reason: "A missing generated-at label is not drift",
+			a:      map[string]string{"team": "platform", GeneratedDateTime: "1"},
+			b:      map[string]string{"team": "platform"},
+			want:   true,
+		},
+		"ValueChanged": {
+			reason: "Labels with different values are not equal",
+			a:      map[string]string{"team": "platform"},
+			b:      map[string]string{"team": "security"},
+			want:   false,
+		},
+		"LabelAdded": {
+			reason: "Labels with different keys are not equal",
+			a:      map[string]string{"team": "platform"},
+			b:      map[string]string{"team": "platform", "environment": "dev"},
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := LabelsEqual(tc.a, tc.b); got != tc.want {
+				t.Errorf("\n%s\nLabelsEqual(...): want %t, got %t\n", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPreserveGeneratedDateTime(t *testing.T) {
+	got := PreserveGeneratedDateTime(ApplyDefaultLabels(), map[string]string{GeneratedDateTime: "1700000000"})
+	if got[GeneratedDateTime] != "1700000000" {
+		t.Errorf("PreserveGeneratedDateTime(...): want live generated-at, got %q", got[GeneratedDateTime])
+	}
+}
+
+func TestClientApplyDefaultLabels(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "example", UID: "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f"}
+
+	policy := &apisv1alpha1.LabelPolicy{
+		Default:           apisv1alpha1.Labels{"team": "platform", "env": "dev"},
+		PropagateMetadata: true,
+	}
+
+	type want struct {
+		labels map[string]string
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    metav1.Object
+		labels map[string]string
+		want   want
+	}{
+		"Merged": {
+			reason: "The provider label, the ProviderConfig's labels and the object's metadata should all be applied",
+			obj:    obj,
+			labels: map[string]string{"app": "web"},
+			want: want{labels: map[string]string{
+				ProviderLabel:                  Provider,
+				"team":                         "platform",
+				"env":                          "dev",
+				apisv1alpha1.ResourceNameLabel: "example",
+				apisv1alpha1.ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+				"app":                          "web",
+			}},
+		},
+		"ResourceLabelsTakePrecedence": {
+			reason: "Labels set on the managed resource should override the defaults and metadata",
+			obj:    obj,
+			labels: map[string]string{"env": "prod", apisv1alpha1.ResourceNameLabel: "renamed"},
+			want: want{labels: map[string]string{
+				ProviderLabel:                  Provider,
+				"team":                         "platform",
+				"env":                          "prod",
+				apisv1alpha1.ResourceNameLabel: "renamed",
+				apisv1alpha1.ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			}},
+		},
+		"LongName": {
+			reason: "An object name longer than a label value should not be rejected",
+			obj:    &metav1.ObjectMeta{Name: strings.Repeat("a", 100), UID: "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f"},
+			want: want{labels: map[string]string{
+				ProviderLabel:                  Provider,
+				"team":                         "platform",
+				"env":                          "dev",
+				apisv1alpha1.ResourceNameLabel: strings.Repeat("a", 54) + "-28165978",
+				apisv1alpha1.ResourceUIDLabel:  "2b1d7e0c-5f4a-4f3e-8c9d-6a7b8c9d0e1f",
+			}},
+		},
+		"Invalid": {
+			reason: "Invalid labels on the managed resource should be rejected",
+			obj:    obj,
+			labels: map[string]string{"env": "prod-"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient("token", WithDefaultLabels(policy.DefaultLabels(tc.obj)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.ApplyDefaultLabels(tc.labels)
+			if tc.want.err != (err != nil) {
+				t.Fatalf("\n%s\nc.ApplyDefaultLabels(...): want error %t, got %v\n", tc.reason, tc.want.err, err)
+			}
+			delete(got, GeneratedDateTime)
+			if diff := cmp.Diff(tc.want.labels, got); diff != "" {
+				t.Errorf("\n%s\nc.ApplyDefaultLabels(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestToLabelSelector(t *testing.T) {
+	type want struct {
+		selector string
+		err      bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		selector *metav1.LabelSelector
+		want     want
+	}{
+		"Nil": {
+			reason: "A nil selector gives an empty string",
+		},
+		"AllOperators": {
+			reason: "Every operator is translated and the requirements are sorted",
+			selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "prod"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "role", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+					{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+					{Key: "managed", Operator: metav1.LabelSelectorOpExists},
+					{Key: "deprecated", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+			want: want{
+				selector: "!deprecated,environment==prod,managed,role in (api,web),tier notin (db)",
+			},
+		},
+		"MissingValues": {
+			reason: "In requires at least one value",
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "role", Operator: metav1.LabelSelectorOpIn},
+				},
+			},
+			want: want{err: true},
+		},
+		"Empty": {
+			reason:   "An empty selector would match everything so is rejected",
+			selector: &metav1.LabelSelector{},
+			want:     want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToLabelSelector(tc.selector)
+			if tc.want.err != (err != nil) {
+				t.Errorf("\n%s\nToLabelSelector(...): want error %t, got %v\n", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.selector, got); diff != "" {
+				t.Errorf("\n%s\nToLabelSelector(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
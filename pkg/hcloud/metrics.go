@@ -0,0 +1,248 @@
+package hcloud
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "hetzner"
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Requests made to the Hetzner API by resource, operation and HTTP status code.",
+	}, []string{"resource", "operation", "code"})
+
+	rateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "rate_limit_remaining",
+		Help:      "Requests left in the Hetzner API rate limit budget, as of the last response.",
+	}, []string{"provider_config", "project"})
+
+	actionWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "action",
+		Name:      "wait_duration_seconds",
+		Help:      "Time spent waiting for Hetzner actions to finish, by command and result: success, error, timeout or request_failed.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"command", "result"})
+
+	managedResourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "managed_resources"),
+		"Managed resources by kind and Ready condition status.",
+		[]string{"kind", "ready"}, nil,
+	)
+)
+
+// Results recorded against the action wait histogram
+const (
+	actionResultSuccess       = "success"
+	actionResultError         = "error"
+	actionResultTimeout       = "timeout"
+	actionResultRequestFailed = "request_failed"
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, rateLimitRemaining, actionWait)
+}
+
+// tracerName identifies the spans of Hetzner API requests
+const tracerName = "github.com/mrsimonemms/provider-hetzner/pkg/hcloud"
+
+// Attribute keys set on the Hetzner request spans
+const (
+	IDKey        = attribute.Key("hetzner.id")
+	ResourceKey  = attribute.Key("hetzner.resource")
+	OperationKey = attribute.Key("hetzner.operation")
+)
+
+// versionSegment matches the API version at the start of the endpoint path
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// instrumentedTransport records metrics and a span for every request sent
+// to the Hetzner API. Spans are children of any span in the request context,
+// such as the one for the external client method making the request.
+type instrumentedTransport struct {
+	next           http.RoundTripper
+	providerConfig string
+	project        string
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource, operation := describeRequest(req)
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(req.URL.Path),
+		ResourceKey.String(resource),
+		OperationKey.String(operation),
+	}
+	if id, ok := requestID(req); ok {
+		attrs = append(attrs, IDKey.Int64(id))
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "hcloud "+resource+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		apiRequests.WithLabelValues(resource, operation, "error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	apiRequests.WithLabelValues(resource, operation, strconv.Itoa(res.StatusCode)).Inc()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+	}
+
+	if remaining, err := strconv.Atoi(res.Header.Get("RateLimit-Remaining")); err == nil {
+		rateLimitRemaining.WithLabelValues(t.providerConfig, t.project).Set(float64(remaining))
+	}
+
+	return res, nil
+}
+
+// requestPath splits the request path into segments, without the API version
+func requestPath(req *http.Request) []string {
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(path) > 0 && versionSegment.MatchString(path[0]) {
+		path = path[1:]
+	}
+
+	return path
+}
+
+// requestID returns the ID of the resource a request is for, if any
+func requestID(req *http.Request) (int64, bool) {
+	path := requestPath(req)
+	if len(path) < 2 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(path[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// describeRequest names the resource and operation of a request from its
+// method and path, such as "servers" and "poweron" for
+// "POST /v1/servers/1/actions/poweron". IDs are left out to keep the label
+// cardinality low.
+func describeRequest(req *http.Request) (resource, operation string) {
+	path := requestPath(req)
+	if len(path) == 0 || path[0] == "" {
+		return "unknown", strings.ToLower(req.Method)
+	}
+
+	resource = path[0]
+
+	switch {
+	case len(path) == 4 && path[2] == "actions":
+		// An action on a resource, such as /servers/1/actions/poweron
+		return resource, path[3]
+	case len(path) == 3 && path[1] == "actions":
+		// An action of a resource type, such as /servers/actions/1
+		return resource, "get_action"
+	case len(path) > 2:
+		return resource, strings.ToLower(req.Method)
+	}
+
+	collection := len(path) == 1
+
+	switch req.Method {
+	case http.MethodGet:
+		if collection {
+			return resource, "list"
+		}
+		return resource, "get"
+	case http.MethodPost:
+		return resource, "create"
+	case http.MethodPut:
+		return resource, "update"
+	case http.MethodDelete:
+		return resource, "delete"
+	}
+
+	return resource, strings.ToLower(req.Method)
+}
+
+// observeActionWait records how long WaitForActionCompletion waited
+func observeActionWait(command, result string, start time.Time) {
+	actionWait.WithLabelValues(command, result).Observe(time.Since(start).Seconds())
+}
+
+// RegisterManagedResourceMetrics counts managed resources by kind and
+// readiness whenever metrics are scraped. Each kind is listed with kube,
+// which should be backed by the manager's cache.
+func RegisterManagedResourceMetrics(kube client.Reader, lists map[string]func() resource.ManagedList) error {
+	return metrics.Registry.Register(&managedResourceCollector{
+		kube:  kube,
+		lists: lists,
+	})
+}
+
+// managedResourceCollector counts managed resources each time it is
+// collected, so the counts follow creations and deletions without any
+// bookkeeping in the controllers
+type managedResourceCollector struct {
+	kube  client.Reader
+	lists map[string]func() resource.ManagedList
+}
+
+func (c *managedResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+}
+
+func (c *managedResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for kind, newList := range c.lists {
+		list := newList()
+		if err := c.kube.List(ctx, list); err != nil {
+			ch <- prometheus.NewInvalidMetric(managedResourcesDesc, err)
+			continue
+		}
+
+		counts := map[corev1.ConditionStatus]int{
+			corev1.ConditionTrue:    0,
+			corev1.ConditionFalse:   0,
+			corev1.ConditionUnknown: 0,
+		}
+		for _, mg := range list.GetItems() {
+			counts[mg.GetCondition(xpv1.TypeReady).Status]++
+		}
+
+		for status, count := range counts {
+			ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(count), kind, string(status))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package hcloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDescribeRequest(t *testing.T) {
+	type want struct {
+		resource  string
+		operation string
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		path   string
+		want   want
+	}{
+		"List": {
+			reason: "A GET of a collection lists it",
+			method: http.MethodGet,
+			path:   "/v1/servers",
+			want:   want{resource: "servers", operation: "list"},
+		},
+		"Get": {
+			reason: "A GET of a single resource gets it, without the ID",
+			method: http.MethodGet,
+			path:   "/v1/servers/42",
+			want:   want{resource: "servers", operation: "get"},
+		},
+		"Create": {
+			reason: "A POST to a collection creates a resource",
+			method: http.MethodPost,
+			path:   "/v1/firewalls",
+			want:   want{resource: "firewalls", operation: "create"},
+		},
+		"Update": {
+			reason: "A PUT updates a resource",
+			method: http.MethodPut,
+			path:   "/v1/volumes/7",
+			want:   want{resource: "volumes", operation: "update"},
+		},
+		"Delete": {
+			reason: "A DELETE deletes a resource",
+			method: http.MethodDelete,
+			path:   "/v1/placement_groups/7",
+			want:   want{resource: "placement_groups", operation: "delete"},
+		},
+		"Action": {
+			reason: "A resource action is named by its command",
+			method: http.MethodPost,
+			path:   "/v1/servers/42/actions/poweron",
+			want:   want{resource: "servers", operation: "poweron"},
+		},
+		"GetAction": {
+			reason: "Polling an action of a resource type is distinguished from getting the resource",
+			method: http.MethodGet,
+			path:   "/v1/servers/actions/99",
+			want:   want{resource: "servers", operation: "get_action"},
+		},
+		"NoVersion": {
+			reason: "Endpoints without a version prefix are described the same way",
+			method: http.MethodGet,
+			path:   "/actions/99",
+			want:   want{resource: "actions", operation: "get"},
+		},
+		"Root": {
+			reason: "Requests without a resource are unknown",
+			method: http.MethodGet,
+			path:   "/v1/",
+			want:   want{resource: "unknown", operation: "get"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+
+			resource, operation := describeRequest(req)
+			got := want{resource: resource, operation: operation}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ndescribeRequest(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInstrumentedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", "3599")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(tp)
+
+	project := "metrics"
+	before := testutil.ToFloat64(apiRequests.WithLabelValues("servers", "get", "404"))
+
+	c, err := NewClient("token",
+		WithProviderConfig("metrics-test", &project),
+		WithClientOptions(hcloud.WithEndpoint(server.URL)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.Server.GetByID(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(before+1, testutil.ToFloat64(apiRequests.WithLabelValues("servers", "get", "404"))); diff != "" {
+		t.Errorf("requests_total: -want, +got:\n%s\n", diff)
+	}
+
+	if diff := cmp.Diff(3599.0, testutil.ToFloat64(rateLimitRemaining.WithLabelValues("metrics-test", project))); diff != "" {
+		t.Errorf("rate_limit_remaining: -want, +got:\n%s\n", diff)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("want 1 span, got %d", len(spans))
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	if diff := cmp.Diff("hcloud servers.get", spans[0].Name()); diff != "" {
+		t.Errorf("span name: -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(int64(1), attrs[IDKey].AsInt64()); diff != "" {
+		t.Errorf("span %s: -want, +got:\n%s\n", IDKey, diff)
+	}
+}
+
+func TestWaitForActionCompletionMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"code":"unauthorized","message":"unable to authenticate"}}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient("token",
+		WithPollInterval(time.Millisecond),
+		WithClientOptions(hcloud.WithEndpoint(server.URL)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action := &hcloud.Action{ID: 1, Command: "metrics_request_failed"}
+	if err := c.WaitForActionCompletion(context.Background(), action); err == nil {
+		t.Fatal("WaitForActionCompletion(...): want error, got nil")
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(actionWait)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]uint64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["command"] == action.Command {
+				got[labels["result"]] += m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	if diff := cmp.Diff(map[string]uint64{actionResultRequestFailed: 1}, got); diff != "" {
+		t.Errorf("wait_duration_seconds: -want, +got:\n%s\n", diff)
+	}
+}